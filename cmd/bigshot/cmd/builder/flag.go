@@ -51,7 +51,7 @@ var FlagRegistry = []Flag{
 		Value:         aws.String(constants.EmptyString),
 		DefValue:      constants.EmptyString,
 		FlagAddMethod: "StringVar",
-		DefinedOn:     []string{"init", "update-template"},
+		DefinedOn:     []string{"init", "update-template", "run", "load"},
 	},
 	{
 		Name:          "dry-run",
@@ -97,6 +97,31 @@ var FlagRegistry = []Flag{
 		FlagAddMethod: "IntVar",
 		DefinedOn:     []string{"run"},
 	},
+	{
+		Name:          "local",
+		Usage:         "Run every target check from this machine instead of lambda workers",
+		Value:         aws.Bool(false),
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"run"},
+	},
+	{
+		Name:          "output",
+		Shorthand:     "o",
+		Usage:         "Format of load test report (text, json, html)",
+		Value:         aws.String(constants.DefaultReportFormat),
+		DefValue:      constants.DefaultReportFormat,
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"load"},
+	},
+	{
+		Name:          "output-file",
+		Usage:         "File to write load test report instead of standard output",
+		Value:         aws.String(constants.EmptyString),
+		DefValue:      constants.EmptyString,
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"load"},
+	},
 	{
 		Name:          "log-file",
 		Usage:         "Log file for bigshot server",
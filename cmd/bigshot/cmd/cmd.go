@@ -68,6 +68,7 @@ func NewRootCommand(out, stderr io.Writer) *cobra.Command {
 	rootCmd.AddCommand(NewUpdateTemplateCommand())
 	rootCmd.AddCommand(NewInitCommand())
 	rootCmd.AddCommand(NewRunCommand())
+	rootCmd.AddCommand(NewLoadCommand())
 	rootCmd.AddCommand(NewStopCommand())
 	rootCmd.AddCommand(NewDeleteCommand())
 	rootCmd.AddCommand(NewDestroyCommand())
@@ -16,13 +16,94 @@ limitations under the License.
 
 package event
 
+import (
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
 type Event struct {
+	Type      string            `json:"type,omitempty"`
 	Target    string            `json:"target"`
 	Port      string            `json:"port"`
 	Method    string            `json:"method"`
 	Body      map[string]string `json:"body,omitempty"`
+	Query     map[string]string `json:"query,omitempty"`
 	Header    map[string]string `json:"header,omitempty"`
 	SlackURLs []string          `json:"slack_urls"`
 	LogLevel  string            `json:"log_level"`
 	Timeout   int               `json:"timeout"`
+
+	// ResultNeeded makes worker return the result instead of sending alarm and saving it
+	ResultNeeded bool `json:"result_needed,omitempty"`
+
+	Ping      *schema.PingOption      `json:"ping,omitempty"`
+	Load      *schema.LoadOption      `json:"load,omitempty"`
+	Checks    *schema.Checks          `json:"checks,omitempty"`
+	DNS       *schema.DNSOption       `json:"dns,omitempty"`
+	TCP       *schema.TCPOption       `json:"tcp,omitempty"`
+	GRPC      *schema.GRPCOption      `json:"grpc,omitempty"`
+	WebSocket *schema.WebSocketOption `json:"websocket,omitempty"`
+
+	Certificate *schema.CertificateOption `json:"certificate,omitempty"`
+	Steps       []schema.Step             `json:"steps,omitempty"`
+	Payload     *schema.Payload           `json:"payload,omitempty"`
+
+	FollowRedirects *schema.RedirectOption `json:"follow_redirects,omitempty"`
+	Auth            *schema.AuthOption     `json:"auth,omitempty"`
+	SigV4           *schema.SigV4Option    `json:"sigv4,omitempty"`
+	TLS             *schema.TLSOption      `json:"tls,omitempty"`
+	Resolve         *schema.ResolveOption  `json:"resolve,omitempty"`
+	Retries         *int                   `json:"retries,omitempty"`
+	RetryBackoff    *int                   `json:"retry_backoff,omitempty"`
+	Samples         *schema.SampleOption   `json:"samples,omitempty"`
+	Warm            *bool                  `json:"warm,omitempty"`
+}
+
+type Response struct {
+	Result *schema.Result `json:"result,omitempty"`
+}
+
+// ToTarget changes event to target configuration
+func (e Event) ToTarget(t string) schema.Target {
+	if len(e.Type) > 0 {
+		t = e.Type
+	}
+
+	target := schema.Target{
+		Type:      aws.String(t),
+		URL:       aws.String(e.Target),
+		Port:      aws.String(e.Port),
+		Method:    aws.String(e.Method),
+		Body:      e.Body,
+		Query:     e.Query,
+		Header:    e.Header,
+		Ping:      e.Ping,
+		Load:      e.Load,
+		Checks:    e.Checks,
+		DNS:       e.DNS,
+		TCP:       e.TCP,
+		GRPC:      e.GRPC,
+		WebSocket: e.WebSocket,
+
+		Certificate: e.Certificate,
+		Steps:       e.Steps,
+		Payload:     e.Payload,
+
+		FollowRedirects: e.FollowRedirects,
+		Auth:            e.Auth,
+		SigV4:           e.SigV4,
+		TLS:             e.TLS,
+		Resolve:         e.Resolve,
+		Retries:         e.Retries,
+		RetryBackoff:    e.RetryBackoff,
+		Samples:         e.Samples,
+		Warm:            e.Warm,
+	}
+
+	if e.Timeout > 0 {
+		target.Timeout = aws.Int(e.Timeout)
+	}
+
+	return target
 }
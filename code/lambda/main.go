@@ -52,11 +52,8 @@ func main() {
 }
 
 // Lambda handler
-func HandleRequest(ctx context.Context, evt event.Event) error {
-	if err := Run(evt); err != nil {
-		return err
-	}
-	return nil
+func HandleRequest(ctx context.Context, evt event.Event) (*event.Response, error) {
+	return Run(evt)
 }
 
 // GetFlags returns flags
@@ -77,25 +74,25 @@ func GetFlags() *Flags {
 }
 
 // Run executes main process of lambda
-func Run(evt event.Event) error {
+func Run(evt event.Event) (*event.Response, error) {
 	envs := env.GetEnvs()
 	if len(evt.LogLevel) == 0 {
 		evt.LogLevel = constants.DefaultWorkerLogLevel
 	}
 
 	if len(envs.Region) == 0 {
-		return fmt.Errorf("region is not specified. please check environment variables")
+		return nil, fmt.Errorf("region is not specified. please check environment variables")
 	}
 	logrus.Infof("this is lambda function in %s", envs.Region)
 
 	switch envs.Mode {
 	case constants.ManagerMode:
-		return workermanager.New().Run(envs)
+		return nil, workermanager.New().Run(envs)
 	case constants.WorkerMode:
 		return worker.NewWorker().Run(envs, evt)
 	}
 
-	return nil
+	return nil, nil
 }
 
 // RunTest executes main process of lambda for test
@@ -35,7 +35,7 @@ func NewWorker() *Worker {
 }
 
 // Run executes worker role
-func (w *Worker) Run(envs env.Env, evt event.Event) error {
+func (w *Worker) Run(envs env.Env, evt event.Event) (*event.Response, error) {
 	if len(envs.RunType) == 0 {
 		envs.RunType = constants.DefaultShooter
 	}
@@ -62,7 +62,7 @@ func (w *Worker) RunTest(workerType string, slackURLs []string) error {
 	}
 
 	for _, evt := range evts {
-		if err := Shoot(
+		if _, err := Shoot(
 			workerType,
 			constants.DefaultRegion,
 			evt,
@@ -75,34 +75,40 @@ func (w *Worker) RunTest(workerType string, slackURLs []string) error {
 }
 
 // Shoot runs api
-func Shoot(t, region string, evt event.Event) error {
+func Shoot(t, region string, evt event.Event) (*event.Response, error) {
 	if len(evt.Target) == 0 {
-		return errors.New("no target specified")
+		return nil, errors.New("no target specified")
 	}
 
 	fmt.Println(evt)
-	shooter := shot.NewShooter(t, region)
-	if shooter == nil {
-		return errors.New("cannot find the right shooter for lambda")
-	}
 	_, err := url.Parse(evt.Target)
 	if err == nil {
-		shooter.SetTarget(evt.Target, evt.Port)
-		shooter.SetMethod(evt.Method)
-		if evt.Body != nil {
-			shooter.SetBody(evt.Body)
+		shooter, err := shot.NewShooterWithTarget(evt.ToTarget(t), region)
+		if err != nil {
+			// e.g. secret reference of target cannot be resolved
+			if !evt.ResultNeeded && len(evt.SlackURLs) > 0 {
+				if sendErr := shot.SendErrorAlarm(evt.SlackURLs, evt.Target, region, err.Error()); sendErr != nil {
+					fmt.Println(sendErr.Error())
+				}
+			}
+			return nil, err
 		}
-		if evt.Header != nil {
-			shooter.SetHeader(evt.Header)
+		shooter.SetLogLevel(evt.LogLevel)
+
+		if evt.ResultNeeded {
+			result, err := shooter.RunWithResult()
+			if err != nil {
+				return nil, err
+			}
+
+			return &event.Response{Result: result}, nil
 		}
-		shooter.SetRate(1)
-		shooter.SetTimeout(evt.Timeout)
+
 		shooter.SetSlackURL(evt.SlackURLs)
-		shooter.SetLogLevel(evt.LogLevel)
 		if err := shooter.Run(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return nil, nil
 }
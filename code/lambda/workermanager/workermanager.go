@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/sirupsen/logrus"
@@ -75,7 +76,6 @@ func Trigger(item map[string]*dynamodb.AttributeValue) error {
 		return err
 	}
 
-	logLevel := *template.Log
 	interval := *template.Interval/len(template.Regions) - 1
 	logrus.Infof("Interval: %d", interval)
 
@@ -97,41 +97,7 @@ func Trigger(item map[string]*dynamodb.AttributeValue) error {
 	}(input, output, &wg)
 
 	f := func(regionData schema.Region, target schema.Target, ch chan error) {
-		timeout := constants.DefaultTargetTimeout
-		if template.Timeout != nil {
-			timeout = *template.Timeout
-		}
-		logrus.Infof("%s, %s, %d", *target.URL, *target.Port, timeout)
-
-		data := map[string]interface{}{
-			"target":                   *target.URL,
-			"port":                     *target.Port,
-			"method":                   *target.Method,
-			"timeout":                  timeout,
-			constants.BigShotSlackURLs: template.SlackURLs,
-		}
-
-		if len(logLevel) > 0 {
-			data["log_level"] = logLevel
-		}
-
-		if target.Body != nil {
-			body := map[string]string{}
-			for k, v := range target.Body {
-				body[k] = v
-			}
-			data["body"] = body
-		}
-
-		if target.Header != nil {
-			header := map[string]string{}
-			for k, v := range target.Header {
-				header[k] = v
-			}
-			data["header"] = header
-		}
-
-		payload, err := json.Marshal(data)
+		payload, err := json.Marshal(NewTriggerData(template, target))
 		if err != nil {
 			ch <- err
 			return
@@ -145,9 +111,9 @@ func Trigger(item map[string]*dynamodb.AttributeValue) error {
 		if target.Internal != nil {
 			internal = *target.Internal
 		}
-		err = lambdaClient.Trigger(regionData.Region, template.Name, payload, internal)
+		_, err = lambdaClient.Trigger(regionData.Region, template.Name, payload, internal)
 		if err == nil {
-			logrus.Infof("function is successfully triggered: %s, %s, %s", *regionData.Region, *target.Port, *target.URL)
+			logrus.Infof("function is successfully triggered: %s, %s, %s", *regionData.Region, aws.StringValue(target.Port), *target.URL)
 		}
 
 		ch <- err
@@ -177,3 +143,124 @@ func Trigger(item map[string]*dynamodb.AttributeValue) error {
 
 	return nil
 }
+
+// NewTriggerData returns payload of worker lambda for target
+// Timeout is the one worker actually uses for each request of the target.
+func NewTriggerData(template schema.Template, target schema.Target) map[string]interface{} {
+	timeout := builder.RequestTimeout(&target, template.Timeout)
+	logrus.Infof("%s, %s, %d", *target.URL, aws.StringValue(target.Port), timeout)
+
+	data := map[string]interface{}{
+		"target":                   *target.URL,
+		"port":                     aws.StringValue(target.Port),
+		"method":                   aws.StringValue(target.Method),
+		"timeout":                  timeout,
+		constants.BigShotSlackURLs: template.SlackURLs,
+	}
+
+	if logLevel := aws.StringValue(template.Log); len(logLevel) > 0 {
+		data["log_level"] = logLevel
+	}
+
+	if target.Type != nil {
+		data["type"] = *target.Type
+	}
+
+	if target.Ping != nil {
+		data["ping"] = target.Ping
+	}
+
+	if target.Load != nil {
+		data["load"] = target.Load
+	}
+
+	if target.DNS != nil {
+		data["dns"] = target.DNS
+	}
+
+	if target.TCP != nil {
+		data["tcp"] = target.TCP
+	}
+
+	if target.GRPC != nil {
+		data["grpc"] = target.GRPC
+	}
+
+	if target.WebSocket != nil {
+		data["websocket"] = target.WebSocket
+	}
+
+	if target.Checks != nil {
+		data["checks"] = target.Checks
+	}
+
+	if target.Certificate != nil {
+		data["certificate"] = target.Certificate
+	}
+
+	if len(target.Steps) > 0 {
+		data["steps"] = target.Steps
+	}
+
+	if target.Payload != nil {
+		data["payload"] = target.Payload
+	}
+
+	if target.FollowRedirects != nil {
+		data["follow_redirects"] = target.FollowRedirects
+	}
+
+	if target.Auth != nil {
+		data["auth"] = target.Auth
+	}
+
+	if target.SigV4 != nil {
+		data["sigv4"] = target.SigV4
+	}
+
+	if target.TLS != nil {
+		data["tls"] = target.TLS
+	}
+
+	if target.Resolve != nil {
+		data["resolve"] = target.Resolve
+	}
+
+	if target.Retries != nil {
+		data["retries"] = target.Retries
+	}
+
+	if target.RetryBackoff != nil {
+		data["retry_backoff"] = target.RetryBackoff
+	}
+
+	if target.Samples != nil {
+		data["samples"] = target.Samples
+	}
+
+	if target.Warm != nil {
+		data["warm"] = target.Warm
+	}
+
+	if len(target.Query) > 0 {
+		data["query"] = target.Query
+	}
+
+	if target.Body != nil {
+		body := map[string]string{}
+		for k, v := range target.Body {
+			body[k] = v
+		}
+		data["body"] = body
+	}
+
+	if target.Header != nil {
+		header := map[string]string{}
+		for k, v := range target.Header {
+			header[k] = v
+		}
+		data["header"] = header
+	}
+
+	return data
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workermanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/code/lambda/event"
+	"github.com/DevopsArtFactory/bigshot/pkg/builder"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestNewTriggerDataTimeout(t *testing.T) {
+	tcs := []struct {
+		name          string
+		target        schema.Target
+		expected      int
+		durationValid bool
+	}{
+		{
+			name:          "timeout of target is forwarded",
+			target:        schema.Target{URL: aws.String("https://example.com"), Timeout: aws.Int(2), Retries: aws.Int(3)},
+			expected:      2,
+			durationValid: true,
+		},
+		{
+			name:          "timeout of template is used without timeout of target",
+			target:        schema.Target{URL: aws.String("https://example.com"), Retries: aws.Int(3)},
+			expected:      30,
+			durationValid: false,
+		},
+	}
+
+	template := schema.Template{Timeout: aws.Int(30)}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := json.Marshal(NewTriggerData(template, tc.target))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var evt event.Event
+			if err := json.Unmarshal(payload, &evt); err != nil {
+				t.Fatal(err)
+			}
+
+			target := evt.ToTarget(constants.HTTPType)
+			if aws.IntValue(target.Timeout) != tc.expected {
+				t.Errorf("expected timeout of worker: %d, got: %d", tc.expected, aws.IntValue(target.Timeout))
+			}
+
+			err = builder.ValidateCheckDuration(&tc.target, template.Timeout)
+			if (err == nil) != tc.durationValid {
+				t.Errorf("expected valid duration: %t, got error: %v", tc.durationValid, err)
+			}
+		})
+	}
+}
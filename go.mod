@@ -4,12 +4,16 @@ go 1.14
 
 require (
 	github.com/AlecAivazis/survey/v2 v2.1.1
+	github.com/HdrHistogram/hdrhistogram-go v1.1.2
+	github.com/PaesslerAG/jsonpath v0.1.1
 	github.com/aws/aws-lambda-go v1.19.1
 	github.com/aws/aws-sdk-go v1.35.5
 	github.com/fatih/color v1.9.0
 	github.com/go-ping/ping v0.0.0-20201022122018-3977ed72668a
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
 	github.com/google/uuid v1.1.2
+	github.com/gorilla/websocket v1.4.2
+	github.com/miekg/dns v1.1.29
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/pkg/errors v0.9.1
@@ -24,6 +28,7 @@ require (
 	golang.org/x/image v0.0.0-20200927104501-e162460cd6b5 // indirect
 	golang.org/x/net v0.0.0-20201024042810-be3efd7ff127
 	golang.org/x/sys v0.0.0-20201022201747-fb209a7c41cd // indirect
+	google.golang.org/grpc v1.43.0
 	gopkg.in/ini.v1 v1.60.2
 	gopkg.in/yaml.v2 v2.3.0
 	k8s.io/kubectl v0.19.0 // indirect
@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// maxTokenResponseSize is the maximum bytes of token response read
+const maxTokenResponseSize = 1 << 16
+
+// DefaultCache keeps OAuth2 tokens while worker is alive
+var DefaultCache = NewTokenCache()
+
+// Provider returns value of Authorization header
+type Provider interface {
+	Authorization(ctx context.Context) (string, error)
+}
+
+// New creates provider of authentication option
+func New(option schema.AuthOption, timeout time.Duration) (Provider, error) {
+	switch aws.StringValue(option.Type) {
+	case constants.BasicAuth:
+		return Basic{
+			Username: aws.StringValue(option.Username),
+			Password: aws.StringValue(option.Password),
+		}, nil
+	case constants.BearerAuth:
+		return Bearer{
+			Token: aws.StringValue(option.Token),
+		}, nil
+	case constants.OAuth2Auth:
+		return OAuth2{
+			TokenURL:     aws.StringValue(option.TokenURL),
+			ClientID:     aws.StringValue(option.ClientID),
+			ClientSecret: aws.StringValue(option.ClientSecret),
+			Scopes:       option.Scopes,
+			Params:       option.Params,
+			Client:       &http.Client{Timeout: timeout},
+			Cache:        DefaultCache,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("authentication type is not supported: %s", aws.StringValue(option.Type))
+}
+
+// Validate checks whether authentication option has every field its type needs
+func Validate(option *schema.AuthOption) error {
+	if option == nil {
+		return nil
+	}
+
+	if option.Type == nil || !tools.IsStringInArray(*option.Type, constants.AllowedAuthTypes) {
+		return fmt.Errorf("type of auth is not allowed: %s", aws.StringValue(option.Type))
+	}
+
+	switch *option.Type {
+	case constants.BasicAuth:
+		if option.Username == nil {
+			return errors.New("username of basic auth is required")
+		}
+	case constants.BearerAuth:
+		if len(aws.StringValue(option.Token)) == 0 {
+			return errors.New("token of bearer auth is required")
+		}
+	case constants.OAuth2Auth:
+		u, err := url.Parse(aws.StringValue(option.TokenURL))
+		if err != nil || len(u.Host) == 0 || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("token_url of oauth2 auth should be an absolute http URL: %s", aws.StringValue(option.TokenURL))
+		}
+
+		if len(aws.StringValue(option.ClientID)) == 0 {
+			return errors.New("client_id of oauth2 auth is required")
+		}
+	}
+
+	return nil
+}
+
+// Basic is HTTP basic authentication
+type Basic struct {
+	Username string
+	Password string
+}
+
+// Authorization returns basic credentials
+func (b Basic) Authorization(ctx context.Context) (string, error) {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(b.Username+":"+b.Password)), nil
+}
+
+// Bearer is static bearer token
+type Bearer struct {
+	Token string
+}
+
+// Authorization returns bearer token
+func (b Bearer) Authorization(ctx context.Context) (string, error) {
+	return "Bearer " + b.Token, nil
+}
+
+// OAuth2 is OAuth2 client credentials grant
+type OAuth2 struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Params       map[string]string
+	Client       *http.Client
+	Cache        *TokenCache
+}
+
+// Authorization returns cached token or requests a new token if it is expired
+func (o OAuth2) Authorization(ctx context.Context) (string, error) {
+	key := o.cacheKey()
+	if token, ok := o.Cache.Get(key); ok {
+		return token.Authorization(), nil
+	}
+
+	token, err := o.FetchToken(ctx)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+	o.Cache.Set(key, token)
+
+	return token.Authorization(), nil
+}
+
+// FetchToken requests a new token to token endpoint
+func (o OAuth2) FetchToken(ctx context.Context) (Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	for k, v := range o.Params {
+		form.Set(k, v)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", constants.FormContentType)
+	req.Header.Set("Accept", constants.JSONContentType)
+
+	// client credentials are form encoded before basic authentication by RFC 6749
+	req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+
+	requested := time.Now()
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("token request failed: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return Token{}, fmt.Errorf("token response cannot be read: %s", err.Error())
+	}
+
+	var data struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	jsonErr := json.Unmarshal(body, &data)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(data.Error) > 0 {
+			return Token{}, fmt.Errorf("token request failed with status %d: %s %s", resp.StatusCode, data.Error, data.ErrorDescription)
+		}
+		return Token{}, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	if jsonErr != nil {
+		return Token{}, fmt.Errorf("token response is not correct: %s", jsonErr.Error())
+	}
+
+	if len(data.AccessToken) == 0 {
+		return Token{}, errors.New("token response has no access_token")
+	}
+
+	lifetime := constants.DefaultTokenLifetime
+	if seconds, err := data.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		lifetime = time.Duration(seconds) * time.Second
+	}
+
+	return Token{
+		AccessToken: data.AccessToken,
+		TokenType:   data.TokenType,
+		Expiry:      requested.Add(lifetime),
+	}, nil
+}
+
+// cacheKey returns key of token which is different for every client and scope
+func (o OAuth2) cacheKey() string {
+	params := make([]string, 0, len(o.Params))
+	for k, v := range o.Params {
+		params = append(params, k+"="+v)
+	}
+	sort.Strings(params)
+
+	return strings.Join([]string{o.TokenURL, o.ClientID, o.ClientSecret, strings.Join(o.Scopes, " "), strings.Join(params, "&")}, "\n")
+}
+
+// Token is access token of OAuth2
+type Token struct {
+	AccessToken string
+	TokenType   string
+	Expiry      time.Time
+}
+
+// Valid returns whether token can be used until the expiry delta
+func (t Token) Valid(now time.Time) bool {
+	return len(t.AccessToken) > 0 && now.Add(constants.TokenExpiryDelta).Before(t.Expiry)
+}
+
+// Authorization returns value of Authorization header with token
+// Token type is case-insensitive but some servers only accept `Bearer`.
+func (t Token) Authorization() string {
+	tokenType := t.TokenType
+	if len(tokenType) == 0 || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenCache keeps tokens until they expire
+type TokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewTokenCache creates an empty token cache
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		tokens: map[string]Token{},
+	}
+}
+
+// Get returns token of key if it is still valid
+func (c *TokenCache) Get(key string) (Token, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[key]
+	if !ok || !token.Valid(time.Now()) {
+		return Token{}, false
+	}
+
+	return token, true
+}
+
+// Set stores token of key
+func (c *TokenCache) Set(key string, token Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens[key] = token
+}
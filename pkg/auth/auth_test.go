@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestBasic(t *testing.T) {
+	provider, err := New(schema.AuthOption{Type: aws.String("basic"), Username: aws.String("user"), Password: aws.String("pass")}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := provider.Authorization(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output != "Basic dXNlcjpwYXNz" {
+		t.Errorf("expected: Basic dXNlcjpwYXNz, got: %s", output)
+	}
+}
+
+func TestOAuth2(t *testing.T) {
+	testData := []struct {
+		Name      string
+		ExpiresIn string
+		Requests  int
+	}{
+		{Name: "cached", ExpiresIn: "3600", Requests: 1},
+		{Name: "refreshed on expiry", ExpiresIn: "1", Requests: 2},
+	}
+
+	for _, td := range testData {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			id, secret, _ := r.BasicAuth()
+			if err := r.ParseForm(); err != nil || id != "client" || secret != "secret" || r.Form.Get("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_client"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%s}`, requests, td.ExpiresIn)
+		}))
+
+		provider := OAuth2{
+			TokenURL:     server.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Client:       server.Client(),
+			Cache:        NewTokenCache(),
+		}
+
+		var output string
+		for i := 0; i < 2; i++ {
+			var err error
+			output, err = provider.Authorization(context.Background())
+			if err != nil {
+				t.Fatalf("%s - %s", td.Name, err.Error())
+			}
+		}
+		server.Close()
+
+		if requests != td.Requests {
+			t.Errorf("%s - expected: %d token requests / output: %d", td.Name, td.Requests, requests)
+		}
+
+		if expected := fmt.Sprintf("Bearer token-%d", td.Requests); output != expected {
+			t.Errorf("%s - expected: %s / output: %s", td.Name, expected, output)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// awsEndpoint matches host of API Gateway and Lambda function URL
+var awsEndpoint = regexp.MustCompile(`^[^.]+\.(execute-api|lambda-url)\.([a-z0-9-]+)\.(amazonaws\.com(\.cn)?|on\.aws)$`)
+
+var (
+	// assumedCredentials keeps credentials of assumed roles while worker is alive
+	// They are refreshed by the provider when they expire.
+	assumedCredentials = map[string]*credentials.Credentials{}
+	assumedMu          sync.Mutex
+)
+
+// SigV4 signs request with AWS Signature Version 4
+type SigV4 struct {
+	Signer  *v4.Signer
+	Service string
+	Region  string
+}
+
+// NewSigV4 creates signer of option for the host
+// Region of the session is used if region is neither specified nor found from host.
+func NewSigV4(option schema.SigV4Option, host string) (*SigV4, error) {
+	sess := client.GetAwsSession()
+
+	service, region := ParseAWSEndpoint(host)
+	if option.Service != nil {
+		service = *option.Service
+	}
+
+	if option.Region != nil {
+		region = *option.Region
+	}
+
+	if len(region) == 0 {
+		region = aws.StringValue(sess.Config.Region)
+	}
+
+	if len(service) == 0 {
+		return nil, fmt.Errorf("service of sigv4 is required for host: %s", host)
+	}
+
+	if len(region) == 0 {
+		return nil, fmt.Errorf("region of sigv4 is required for host: %s", host)
+	}
+
+	creds := sess.Config.Credentials
+	if option.RoleARN != nil {
+		assumedMu.Lock()
+		if _, ok := assumedCredentials[*option.RoleARN]; !ok {
+			assumedCredentials[*option.RoleARN] = stscreds.NewCredentials(sess, *option.RoleARN)
+		}
+		creds = assumedCredentials[*option.RoleARN]
+		assumedMu.Unlock()
+	}
+
+	return &SigV4{
+		Signer:  v4.NewSigner(creds),
+		Service: service,
+		Region:  region,
+	}, nil
+}
+
+// Retrieve gets credentials so that failure is found before request
+func (s *SigV4) Retrieve() error {
+	_, err := s.Signer.Credentials.Get()
+	return err
+}
+
+// Sign adds signature headers to request
+// Body should be the same bytes as body of request.
+func (s *SigV4) Sign(req *http.Request, body []byte) error {
+	_, err := s.Signer.Sign(req, bytes.NewReader(body), s.Service, s.Region, time.Now())
+	return err
+}
+
+// ParseAWSEndpoint returns signing service and region of API Gateway or Lambda function URL host
+// Empty strings are returned for other hosts.
+func ParseAWSEndpoint(host string) (string, string) {
+	matched := awsEndpoint.FindStringSubmatch(strings.ToLower(host))
+	if matched == nil {
+		return constants.EmptyString, constants.EmptyString
+	}
+
+	if matched[1] == "lambda-url" {
+		return constants.LambdaService, matched[2]
+	}
+
+	return constants.ExecuteAPIService, matched[2]
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+func TestParseAWSEndpoint(t *testing.T) {
+	testData := []struct {
+		Host    string
+		Service string
+		Region  string
+	}{
+		{Host: "abcdef1234.execute-api.ap-northeast-2.amazonaws.com", Service: "execute-api", Region: "ap-northeast-2"},
+		{Host: "abcdef1234.execute-api.cn-north-1.amazonaws.com.cn", Service: "execute-api", Region: "cn-north-1"},
+		{Host: "abcdefghijklmnop.lambda-url.us-east-1.on.aws", Service: "lambda", Region: "us-east-1"},
+		{Host: "api.example.com", Service: "", Region: ""},
+	}
+
+	for _, td := range testData {
+		service, region := ParseAWSEndpoint(td.Host)
+		if service != td.Service || region != td.Region {
+			t.Errorf("%s - expected: %s %s / output: %s %s", td.Host, td.Service, td.Region, service, region)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	signer := SigV4{
+		Signer:  v4.NewSigner(credentials.NewStaticCredentials("AKID", "SECRET", "SESSION")),
+		Service: "execute-api",
+		Region:  "us-east-1",
+	}
+
+	body := []byte(`{"id":1}`)
+	req, err := http.NewRequest(http.MethodPost, "https://abcdef1234.execute-api.us-east-1.amazonaws.com/prod/orders", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := signer.Sign(req, body); err != nil {
+		t.Fatal(err)
+	}
+
+	if prefix := "AWS4-HMAC-SHA256 Credential=AKID/"; !strings.HasPrefix(req.Header.Get("Authorization"), prefix) {
+		t.Errorf("expected prefix: %s, got: %s", prefix, req.Header.Get("Authorization"))
+	}
+
+	if !strings.Contains(req.Header.Get("Authorization"), "/us-east-1/execute-api/aws4_request") {
+		t.Errorf("scope is not correct: %s", req.Header.Get("Authorization"))
+	}
+
+	if req.Header.Get("X-Amz-Security-Token") != "SESSION" {
+		t.Errorf("session token is not set: %s", req.Header.Get("X-Amz-Security-Token"))
+	}
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package builder
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/sirupsen/logrus"
@@ -30,8 +35,11 @@ import (
 	"gopkg.in/ini.v1"
 	"gopkg.in/yaml.v2"
 
+	"github.com/DevopsArtFactory/bigshot/pkg/auth"
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/secret"
 	"github.com/DevopsArtFactory/bigshot/pkg/tools"
 )
 
@@ -42,13 +50,16 @@ type Builder struct {
 }
 
 type Flags struct {
-	Region    string `json:"region"`
-	Config    string `json:"config"`
-	ZipFile   string `json:"zip_file"`
-	LogFile   string `json:"log_file"`
-	AllRegion bool   `json:"all"`
-	DryRun    bool   `json:"dry_run"`
-	Interval  int    `json:"interval"`
+	Region     string `json:"region"`
+	Config     string `json:"config"`
+	ZipFile    string `json:"zip_file"`
+	LogFile    string `json:"log_file"`
+	Output     string `json:"output"`
+	OutputFile string `json:"output_file"`
+	AllRegion  bool   `json:"all"`
+	DryRun     bool   `json:"dry_run"`
+	Local      bool   `json:"local"`
+	Interval   int    `json:"interval"`
 }
 
 // Validate checks the validation of configuration
@@ -66,17 +77,137 @@ func (b *Builder) Validate() error {
 	}
 
 	hasInternal := false
-	for _, target := range b.Config.Targets {
-		if !tools.IsStringInArray(*target.Method, constants.AllowedMethods) {
-			return fmt.Errorf("method for API check is not allowed: %s", *target.Method)
+	for i := range b.Config.Targets {
+		target := &b.Config.Targets[i]
+		if target.Type != nil && !tools.IsStringInArray(*target.Type, constants.AllowedTypes) {
+			return fmt.Errorf("type of target is not allowed: %s", *target.Type)
 		}
 
-		if target.URL == nil || target.Port == nil {
-			return fmt.Errorf("URL and port are required")
+		if err := ValidateAuth(target); err != nil {
+			return err
 		}
 
-		if *target.Method == "GET" && len(target.Body) > 0 {
-			return errors.New("you cannot set body values to GET request")
+		if err := ValidateTLS(target); err != nil {
+			return err
+		}
+
+		if err := ValidateResolve(target); err != nil {
+			return err
+		}
+
+		if err := ValidateRetries(target); err != nil {
+			return err
+		}
+
+		if err := ValidateSamples(target); err != nil {
+			return err
+		}
+
+		if err := ValidateWarm(target); err != nil {
+			return err
+		}
+
+		if err := ValidateCheckDuration(target, b.Config.Timeout); err != nil {
+			return err
+		}
+
+		if target.Type != nil && (*target.Type == constants.PingType || *target.Type == constants.DNSType) {
+			if target.URL == nil {
+				return fmt.Errorf("URL is required")
+			}
+
+			if target.DNS != nil && target.DNS.RecordType != nil && !tools.IsStringInArray(strings.ToUpper(*target.DNS.RecordType), constants.AllowedRecordTypes) {
+				return fmt.Errorf("record type is not allowed: %s", *target.DNS.RecordType)
+			}
+			continue
+		}
+
+		if target.Type != nil && (*target.Type == constants.TCPType || *target.Type == constants.GRPCType) {
+			if target.URL == nil || target.Port == nil {
+				return fmt.Errorf("URL and port are required")
+			}
+
+			if target.TCP != nil && target.TCP.Expected != nil {
+				if _, err := regexp.Compile(*target.TCP.Expected); err != nil {
+					return fmt.Errorf("expected response of tcp target is not correct: %s", err.Error())
+				}
+			}
+			continue
+		}
+
+		if target.Type != nil && *target.Type == constants.WebSocketType {
+			if err := NormalizeURL(target); err != nil {
+				return err
+			}
+
+			if target.WebSocket != nil && target.WebSocket.Expected != nil {
+				if _, err := regexp.Compile(*target.WebSocket.Expected); err != nil {
+					return fmt.Errorf("expected reply of websocket target is not correct: %s", err.Error())
+				}
+			}
+
+			if target.WebSocket != nil && target.WebSocket.MessageTimeout != nil && *target.WebSocket.MessageTimeout <= 0 {
+				return fmt.Errorf("message timeout of websocket target should be positive: %d", *target.WebSocket.MessageTimeout)
+			}
+			continue
+		}
+
+		if err := checker.ValidateRedirect(target.FollowRedirects); err != nil {
+			return err
+		}
+
+		if len(target.Steps) > 0 {
+			if err := NormalizeURL(target); err != nil {
+				return err
+			}
+
+			for _, step := range target.Steps {
+				if step.Method != nil && !tools.IsStringInArray(*step.Method, constants.AllowedMethods) {
+					return fmt.Errorf("method of step is not allowed: %s", *step.Method)
+				}
+
+				method := aws.StringValue(step.Method)
+				if len(method) == 0 {
+					method = constants.DefaultStepMethod
+				}
+
+				if err := ValidateRequestBody(method, step.Body, step.Payload); err != nil {
+					return err
+				}
+			}
+
+			if err := checker.ValidateSteps(target.Steps); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if target.Method == nil || !tools.IsStringInArray(*target.Method, constants.AllowedMethods) {
+			return fmt.Errorf("method for API check is not allowed: %s", aws.StringValue(target.Method))
+		}
+
+		if err := NormalizeURL(target); err != nil {
+			return err
+		}
+
+		if err := ValidateRequestBody(*target.Method, target.Body, target.Payload); err != nil {
+			return err
+		}
+
+		if err := checker.Validate(target.Checks); err != nil {
+			return err
+		}
+
+		if target.Certificate != nil {
+			for _, threshold := range target.Certificate.ExpiryThresholds {
+				if threshold <= 0 {
+					return fmt.Errorf("expiry threshold of certificate should be positive: %d", threshold)
+				}
+			}
+		}
+
+		if target.Load != nil && target.Load.Duration != nil && b.Config.Timeout != nil && *target.Load.Duration >= *b.Config.Timeout {
+			return fmt.Errorf("duration of load test should be shorter than timeout: %d", *target.Load.Duration)
 		}
 	}
 
@@ -96,6 +227,310 @@ func (b *Builder) Validate() error {
 	return nil
 }
 
+// ValidateAuth checks whether auth and sigv4 can be used for the target
+// Authorization header cannot be used with them because one of them would be ignored.
+func ValidateAuth(target *schema.Target) error {
+	if target.SigV4 != nil {
+		if err := ValidateSigV4(target); err != nil {
+			return err
+		}
+	}
+
+	if target.Auth == nil {
+		return nil
+	}
+
+	if target.Type != nil && !tools.IsStringInArray(*target.Type, []string{constants.HTTPType, constants.LoadType, constants.WebSocketType}) {
+		return fmt.Errorf("auth is not supported for %s target", *target.Type)
+	}
+
+	for k := range target.Header {
+		if strings.EqualFold(k, "Authorization") {
+			return errors.New("authorization header cannot be used with auth")
+		}
+	}
+
+	return auth.Validate(target.Auth)
+}
+
+// ValidateSigV4 checks whether requests of the target can be signed
+func ValidateSigV4(target *schema.Target) error {
+	if target.Type != nil && *target.Type != constants.HTTPType {
+		return fmt.Errorf("sigv4 is not supported for %s target", *target.Type)
+	}
+
+	if target.Auth != nil {
+		return errors.New("sigv4 cannot be used with auth")
+	}
+
+	for k := range target.Header {
+		if strings.EqualFold(k, "Authorization") {
+			return errors.New("authorization header cannot be used with sigv4")
+		}
+	}
+
+	if target.SigV4.RoleARN != nil && !strings.HasPrefix(*target.SigV4.RoleARN, "arn:") {
+		return fmt.Errorf("role_arn of sigv4 is not correct: %s", *target.SigV4.RoleARN)
+	}
+
+	if target.SigV4.Service == nil && target.URL != nil {
+		u, err := tools.ParseTargetURL(*target.URL, aws.StringValue(target.Port))
+		if err != nil {
+			return err
+		}
+
+		if service, _ := auth.ParseAWSEndpoint(u.Hostname()); len(service) == 0 {
+			return fmt.Errorf("service of sigv4 is required for host: %s", u.Hostname())
+		}
+	}
+
+	return nil
+}
+
+// ValidateTLS checks TLS option of the target
+// PEM values which are secret references are checked only when worker loads them.
+func ValidateTLS(target *schema.Target) error {
+	option := target.TLS
+	if option == nil {
+		return nil
+	}
+
+	if target.Type != nil && !tools.IsStringInArray(*target.Type, []string{constants.HTTPType, constants.LoadType, constants.WebSocketType, constants.GRPCType}) {
+		return fmt.Errorf("tls is not supported for %s target", *target.Type)
+	}
+
+	if target.GRPC != nil && target.GRPC.TLS != nil && !*target.GRPC.TLS {
+		return errors.New("tls option cannot be used when tls of grpc is false")
+	}
+
+	if (option.ClientCert == nil) != (option.ClientKey == nil) {
+		return errors.New("both client_cert and client_key are required")
+	}
+
+	if option.ClientCert != nil && !secret.HasReference(*option.ClientCert) && !secret.HasReference(*option.ClientKey) {
+		if _, err := tls.X509KeyPair([]byte(*option.ClientCert), []byte(*option.ClientKey)); err != nil {
+			return fmt.Errorf("client certificate is not correct: %s", err.Error())
+		}
+	}
+
+	if option.CA != nil && !secret.HasReference(*option.CA) && !x509.NewCertPool().AppendCertsFromPEM([]byte(*option.CA)) {
+		return errors.New("CA bundle has no certificate")
+	}
+
+	return nil
+}
+
+// ValidateResolve checks IPs which host of the target is pinned to
+func ValidateResolve(target *schema.Target) error {
+	option := target.Resolve
+	if option == nil {
+		return nil
+	}
+
+	if target.Type != nil && *target.Type != constants.HTTPType {
+		return fmt.Errorf("resolve is not supported for %s target", *target.Type)
+	}
+
+	if len(option.IPs) > 0 && aws.BoolValue(option.All) {
+		return errors.New("only one of ips and all of resolve should be set")
+	}
+
+	if len(option.IPs) == 0 && !aws.BoolValue(option.All) {
+		return errors.New("ips or all of resolve is required")
+	}
+
+	for _, ip := range option.IPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("ip of resolve is not correct: %s", ip)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRetries checks retries of the target
+func ValidateRetries(target *schema.Target) error {
+	if target.Retries == nil && target.RetryBackoff == nil {
+		return nil
+	}
+
+	if target.Type != nil && *target.Type != constants.HTTPType {
+		return fmt.Errorf("retries are not supported for %s target", *target.Type)
+	}
+
+	if retries := aws.IntValue(target.Retries); retries < 0 || retries > constants.MaxRetries {
+		return fmt.Errorf("retries should be between 0 and %d: %d", constants.MaxRetries, retries)
+	}
+
+	if target.RetryBackoff != nil && *target.RetryBackoff <= 0 {
+		return fmt.Errorf("retry_backoff should be positive: %d", *target.RetryBackoff)
+	}
+
+	return nil
+}
+
+// ValidateSamples checks sample option of the target
+func ValidateSamples(target *schema.Target) error {
+	option := target.Samples
+	if option == nil {
+		return nil
+	}
+
+	if target.Type != nil && *target.Type != constants.HTTPType {
+		return fmt.Errorf("samples are not supported for %s target", *target.Type)
+	}
+
+	if count := aws.IntValue(option.Count); option.Count != nil && (count < 1 || count > constants.MaxSamples) {
+		return fmt.Errorf("count of samples should be between 1 and %d: %d", constants.MaxSamples, count)
+	}
+
+	if option.Interval != nil && *option.Interval < 0 {
+		return fmt.Errorf("interval of samples should not be negative: %d", *option.Interval)
+	}
+
+	return nil
+}
+
+// ValidateWarm checks whether warm request can be sent to the target
+func ValidateWarm(target *schema.Target) error {
+	if !aws.BoolValue(target.Warm) {
+		return nil
+	}
+
+	if target.Type != nil && *target.Type != constants.HTTPType {
+		return fmt.Errorf("warm request is not supported for %s target", *target.Type)
+	}
+
+	if len(target.Steps) > 0 {
+		return errors.New("warm request is not supported with steps")
+	}
+
+	return nil
+}
+
+// RequestTimeout returns timeout of each request to the target in seconds
+// Worker uses timeout of target if it is set, otherwise timeout of template.
+func RequestTimeout(target *schema.Target, timeout *int) int {
+	if target.Timeout != nil {
+		return *target.Timeout
+	}
+
+	if timeout != nil {
+		return *timeout
+	}
+
+	return constants.DefaultTargetTimeout
+}
+
+// ValidateCheckDuration checks whether every attempt and sample of the target finishes before timeout of template
+func ValidateCheckDuration(target *schema.Target, timeout *int) error {
+	if timeout == nil || (target.Retries == nil && target.Samples == nil && !aws.BoolValue(target.Warm)) {
+		return nil
+	}
+
+	attempt := time.Duration(RequestTimeout(target, timeout)) * time.Second
+	if aws.BoolValue(target.Warm) {
+		attempt *= 2
+	}
+
+	if option := target.Samples; option != nil && option.Count != nil {
+		count := time.Duration(*option.Count)
+		attempt = count*attempt + (count-1)*time.Duration(aws.IntValue(option.Interval))*time.Millisecond
+	}
+
+	backoff := constants.DefaultRetryBackoff
+	if target.RetryBackoff != nil {
+		backoff = *target.RetryBackoff
+	}
+
+	retries := aws.IntValue(target.Retries)
+	total := time.Duration(retries+1) * attempt
+	for i := 0; i < retries; i++ {
+		total += tools.GetExponentialTime(backoff, i)
+	}
+
+	if total >= time.Duration(*timeout)*time.Second {
+		return fmt.Errorf("check can take %s which should be shorter than timeout: %d, set timeout of target to limit each request", total.String(), *timeout)
+	}
+
+	return nil
+}
+
+// NormalizeURL validates URL of target and changes it to full URL with scheme
+// Port is merged into URL so that it is not required any more.
+func NormalizeURL(target *schema.Target) error {
+	if target.URL == nil {
+		return errors.New("URL is required")
+	}
+
+	u, err := tools.ParseTargetURL(*target.URL, aws.StringValue(target.Port))
+	if err != nil {
+		return err
+	}
+
+	target.URL = aws.String(u.String())
+	target.Port = nil
+
+	return nil
+}
+
+// ValidateRequestBody checks body and payload of request
+func ValidateRequestBody(method string, body map[string]string, payload *schema.Payload) error {
+	if body == nil && payload == nil {
+		return nil
+	}
+
+	if tools.IsStringInArray(method, constants.MethodsWithoutBody) {
+		return fmt.Errorf("you cannot set body values to %s request", method)
+	}
+
+	if payload == nil {
+		return nil
+	}
+
+	if body != nil {
+		return errors.New("body and payload cannot be used together")
+	}
+
+	count := 0
+	for _, set := range []bool{payload.Raw != nil, payload.JSON != nil, payload.Form != nil, payload.Multipart != nil, payload.File != nil} {
+		if set {
+			count++
+		}
+	}
+
+	if count != 1 {
+		return errors.New("one of raw, json, form, multipart and file is required for payload")
+	}
+
+	for _, field := range payload.Multipart {
+		if field.Name == nil {
+			return errors.New("name of multipart field is required")
+		}
+
+		if (field.Value == nil) == (field.File == nil) {
+			return fmt.Errorf("one of value and file is required for multipart field: %s", *field.Name)
+		}
+	}
+
+	return nil
+}
+
+// NormalizePayloads changes JSON payloads decoded from YAML so that they can be stored and encoded
+func NormalizePayloads(template *schema.Template) {
+	for i := range template.Targets {
+		if payload := template.Targets[i].Payload; payload != nil {
+			payload.JSON = tools.NormalizeYAML(payload.JSON)
+		}
+
+		for j := range template.Targets[i].Steps {
+			if payload := template.Targets[i].Steps[j].Payload; payload != nil {
+				payload.JSON = tools.NormalizeYAML(payload.JSON)
+			}
+		}
+	}
+}
+
 // ValidateFlags checks validation of flags
 func ValidateFlags(flags Flags) error {
 	if len(flags.Region) > 0 && !tools.IsStringInArray(flags.Region, constants.AllAWSRegions) {
@@ -106,6 +541,10 @@ func ValidateFlags(flags Flags) error {
 		return fmt.Errorf("file does not exist: %s", flags.ZipFile)
 	}
 
+	if len(flags.Output) > 0 && !tools.IsStringInArray(flags.Output, constants.AllowedReportFormats) {
+		return fmt.Errorf("report format is not supported: %s", flags.Output)
+	}
+
 	return nil
 }
 
@@ -146,6 +585,7 @@ func ParseConfig(template *schema.Template, configFile string) error {
 	if err != nil {
 		return err
 	}
+	NormalizePayloads(template)
 
 	return nil
 }
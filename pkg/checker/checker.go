@@ -0,0 +1,384 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// DefaultStatus is expected status code when no status check is specified
+const DefaultStatus = "200"
+
+// Assert evaluates every check with response and returns assertions
+func Assert(checks *schema.Checks, response schema.Response, total time.Duration) []schema.Assertion {
+	if checks == nil {
+		checks = &schema.Checks{}
+	}
+
+	assertions := []schema.Assertion{AssertStatus(checks.Status, response.StatusCode)}
+
+	for _, check := range checks.Headers {
+		assertions = append(assertions, AssertHeader(check, response.Header))
+	}
+
+	for _, check := range checks.Body {
+		assertions = append(assertions, AssertBody(check, response.Body))
+	}
+
+	for _, check := range checks.JSONPath {
+		assertions = append(assertions, AssertJSONPath(check, response.Body))
+	}
+
+	if checks.MaxLatency != nil {
+		assertions = append(assertions, AssertLatency(*checks.MaxLatency, total))
+	}
+
+	return assertions
+}
+
+// AssertStatus checks whether status code is one of expected codes
+func AssertStatus(expected []string, code int) schema.Assertion {
+	if len(expected) == 0 {
+		expected = []string{DefaultStatus}
+	}
+
+	assertion := schema.Assertion{
+		Name:     "status",
+		Expected: strings.Join(expected, ", "),
+		Actual:   strconv.Itoa(code),
+	}
+
+	for _, e := range expected {
+		matched, err := MatchStatus(e, code)
+		if err != nil {
+			assertion.Actual = err.Error()
+			return assertion
+		}
+
+		if matched {
+			assertion.Passed = true
+			break
+		}
+	}
+
+	return assertion
+}
+
+// MatchStatus checks status code with a code, a class like `2xx` or a range like `200-299`
+func MatchStatus(expected string, code int) (bool, error) {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+
+	if len(expected) == 3 && strings.HasSuffix(expected, "xx") {
+		class, err := strconv.Atoi(expected[:1])
+		if err != nil {
+			return false, fmt.Errorf("status class is not correct: %s", expected)
+		}
+		return code/100 == class, nil
+	}
+
+	if strings.Contains(expected, "-") {
+		split := strings.SplitN(expected, "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(split[0]))
+		if err != nil {
+			return false, fmt.Errorf("status range is not correct: %s", expected)
+		}
+
+		to, err := strconv.Atoi(strings.TrimSpace(split[1]))
+		if err != nil || to < from {
+			return false, fmt.Errorf("status range is not correct: %s", expected)
+		}
+		return from <= code && code <= to, nil
+	}
+
+	status, err := strconv.Atoi(expected)
+	if err != nil {
+		return false, fmt.Errorf("status code is not correct: %s", expected)
+	}
+
+	return status == code, nil
+}
+
+// AssertHeader checks response header
+func AssertHeader(check schema.HeaderCheck, header map[string][]string) schema.Assertion {
+	name := aws.StringValue(check.Name)
+	values, exists := http.Header(header)[http.CanonicalHeaderKey(name)]
+	actual := strings.Join(values, ", ")
+
+	assertion := schema.Assertion{
+		Name:   fmt.Sprintf("header %s", name),
+		Actual: actual,
+	}
+
+	switch {
+	case check.Equals != nil:
+		assertion.Expected = *check.Equals
+		assertion.Passed = exists && actual == *check.Equals
+	case check.Regex != nil:
+		assertion.Expected = fmt.Sprintf("~ /%s/", *check.Regex)
+		assertion.Passed = exists && matchRegex(*check.Regex, actual, &assertion)
+	default:
+		assertion.Expected = "exists"
+		assertion.Passed = exists
+	}
+
+	if !exists {
+		assertion.Actual = "missing"
+	}
+
+	return assertion
+}
+
+// AssertBody checks response body
+func AssertBody(check schema.BodyCheck, body []byte) schema.Assertion {
+	if check.Regex != nil {
+		return AssertRegex("body regex", *check.Regex, body)
+	}
+
+	assertion := schema.Assertion{
+		Name:     "body contains",
+		Expected: aws.StringValue(check.Contains),
+		Actual:   "not found",
+	}
+
+	if strings.Contains(string(body), aws.StringValue(check.Contains)) {
+		assertion.Passed = true
+		assertion.Actual = "found"
+	}
+
+	return assertion
+}
+
+// AssertRegex checks whether data matches the regular expression
+func AssertRegex(name, pattern string, data []byte) schema.Assertion {
+	assertion := schema.Assertion{
+		Name:     name,
+		Expected: fmt.Sprintf("~ /%s/", pattern),
+		Actual:   "not matched",
+	}
+
+	if matchRegex(pattern, string(data), &assertion) {
+		assertion.Passed = true
+		assertion.Actual = "matched"
+	}
+
+	return assertion
+}
+
+// AssertJSONPath checks a value of JSON body
+func AssertJSONPath(check schema.JSONPathCheck, body []byte) schema.Assertion {
+	path := aws.StringValue(check.Path)
+	assertion := schema.Assertion{
+		Name:     fmt.Sprintf("json_path %s", path),
+		Expected: aws.StringValue(check.Equals),
+	}
+
+	actual, err := GetJSONPath(path, body)
+	if err != nil {
+		assertion.Actual = err.Error()
+		return assertion
+	}
+
+	assertion.Actual = actual
+	assertion.Passed = check.Equals == nil || actual == *check.Equals
+
+	return assertion
+}
+
+// GetJSONPath returns the value of JSONPath from JSON body as string
+func GetJSONPath(path string, body []byte) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", errors.New("body is not JSON")
+	}
+
+	value, err := jsonpath.Get(path, data)
+	if err != nil {
+		return "", err
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// AssertLatency checks total latency of request
+func AssertLatency(max int, total time.Duration) schema.Assertion {
+	limit := time.Duration(max) * time.Millisecond
+
+	return schema.Assertion{
+		Name:     "max_latency",
+		Expected: fmt.Sprintf("<= %s", limit),
+		Actual:   total.String(),
+		Passed:   total <= limit,
+	}
+}
+
+// AssertDNS evaluates DNS option with DNS result and returns assertions
+func AssertDNS(option schema.DNSOption, result schema.DNSResult) []schema.Assertion {
+	var answers []schema.DNSAnswer
+	for _, answer := range result.Answers {
+		if answer.Type == result.RecordType {
+			answers = append(answers, answer)
+		}
+	}
+
+	exists := option.Exists == nil || *option.Exists
+	assertions := []schema.Assertion{
+		{
+			Name:     fmt.Sprintf("%s record exists", result.RecordType),
+			Expected: strconv.FormatBool(exists),
+			Actual:   fmt.Sprintf("%d answers (%s)", len(answers), result.Rcode),
+			Passed:   (len(answers) > 0) == exists,
+		},
+	}
+
+	for _, expected := range option.Expected {
+		assertion := schema.Assertion{
+			Name:     "answer",
+			Expected: expected,
+			Actual:   "not found",
+		}
+
+		for _, answer := range answers {
+			if normalizeAnswer(answer.Value) == normalizeAnswer(expected) {
+				assertion.Actual = answer.Value
+				assertion.Passed = true
+				break
+			}
+		}
+		assertions = append(assertions, assertion)
+	}
+
+	if option.MinTTL == nil && option.MaxTTL == nil {
+		return assertions
+	}
+
+	for _, answer := range answers {
+		if option.MinTTL != nil {
+			assertions = append(assertions, schema.Assertion{
+				Name:     fmt.Sprintf("min_ttl %s", answer.Value),
+				Expected: fmt.Sprintf(">= %d", *option.MinTTL),
+				Actual:   strconv.FormatUint(uint64(answer.TTL), 10),
+				Passed:   int64(answer.TTL) >= int64(*option.MinTTL),
+			})
+		}
+
+		if option.MaxTTL != nil {
+			assertions = append(assertions, schema.Assertion{
+				Name:     fmt.Sprintf("max_ttl %s", answer.Value),
+				Expected: fmt.Sprintf("<= %d", *option.MaxTTL),
+				Actual:   strconv.FormatUint(uint64(answer.TTL), 10),
+				Passed:   int64(answer.TTL) <= int64(*option.MaxTTL),
+			})
+		}
+	}
+
+	return assertions
+}
+
+// normalizeAnswer removes differences of case and trailing dot of domain names
+func normalizeAnswer(answer string) string {
+	fields := strings.Fields(strings.ToLower(answer))
+	for i, field := range fields {
+		fields[i] = strings.TrimSuffix(field, ".")
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// Validate checks whether checks are well-formed
+func Validate(checks *schema.Checks) error {
+	if checks == nil {
+		return nil
+	}
+
+	for _, status := range checks.Status {
+		if _, err := MatchStatus(status, 0); err != nil {
+			return err
+		}
+	}
+
+	for _, check := range checks.Headers {
+		if check.Name == nil {
+			return errors.New("name of header check is required")
+		}
+
+		if check.Regex != nil {
+			if _, err := regexp.Compile(*check.Regex); err != nil {
+				return fmt.Errorf("regex of header check is not correct: %s", err.Error())
+			}
+		}
+	}
+
+	for _, check := range checks.Body {
+		if check.Contains == nil && check.Regex == nil {
+			return errors.New("contains or regex of body check is required")
+		}
+
+		if check.Regex != nil {
+			if _, err := regexp.Compile(*check.Regex); err != nil {
+				return fmt.Errorf("regex of body check is not correct: %s", err.Error())
+			}
+		}
+	}
+
+	for _, check := range checks.JSONPath {
+		if check.Path == nil {
+			return errors.New("path of json_path check is required")
+		}
+
+		if _, err := jsonpath.New(*check.Path); err != nil {
+			return fmt.Errorf("json_path is not correct: %s", err.Error())
+		}
+	}
+
+	if checks.MaxLatency != nil && *checks.MaxLatency <= 0 {
+		return fmt.Errorf("max_latency should be positive: %d", *checks.MaxLatency)
+	}
+
+	return nil
+}
+
+// matchRegex matches s with pattern and records compile error to assertion
+func matchRegex(pattern, s string, assertion *schema.Assertion) bool {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		assertion.Actual = err.Error()
+		return false
+	}
+
+	return re.MatchString(s)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestMatchStatus(t *testing.T) {
+	testData := []struct {
+		Expected string
+		Code     int
+		Output   bool
+		Error    bool
+	}{
+		{Expected: "200", Code: 200, Output: true},
+		{Expected: "201", Code: 200, Output: false},
+		{Expected: "2xx", Code: 204, Output: true},
+		{Expected: "3XX", Code: 404, Output: false},
+		{Expected: "200-299", Code: 299, Output: true},
+		{Expected: "200-299", Code: 302, Output: false},
+		{Expected: "299-200", Code: 250, Error: true},
+		{Expected: "ok", Code: 200, Error: true},
+	}
+
+	for _, td := range testData {
+		output, err := MatchStatus(td.Expected, td.Code)
+		if (err != nil) != td.Error {
+			t.Errorf("%s: unexpected error: %v", td.Expected, err)
+		}
+
+		if output != td.Output {
+			t.Errorf("%s with %d - expected: %t / output: %t", td.Expected, td.Code, td.Output, output)
+		}
+	}
+}
+
+func TestAssert(t *testing.T) {
+	response := schema.Response{
+		StatusCode: 201,
+		Header: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+		Body: []byte(`{"status":"ok","items":[{"id":3}]}`),
+	}
+
+	testData := []struct {
+		Name   string
+		Checks *schema.Checks
+		Output []bool
+	}{
+		{
+			Name:   "default status",
+			Checks: nil,
+			Output: []bool{false},
+		},
+		{
+			Name: "every check",
+			Checks: &schema.Checks{
+				Status: []string{"2xx"},
+				Headers: []schema.HeaderCheck{
+					{Name: aws.String("content-type"), Equals: aws.String("application/json")},
+					{Name: aws.String("Content-Type"), Regex: aws.String("^text/")},
+					{Name: aws.String("X-Missing")},
+				},
+				Body: []schema.BodyCheck{
+					{Contains: aws.String(`"status":"ok"`)},
+					{Regex: aws.String(`"id":\d+`)},
+				},
+				JSONPath: []schema.JSONPathCheck{
+					{Path: aws.String("$.status"), Equals: aws.String("ok")},
+					{Path: aws.String("$.items[0].id"), Equals: aws.String("3")},
+					{Path: aws.String("$.missing"), Equals: aws.String("ok")},
+				},
+				MaxLatency: aws.Int(100),
+			},
+			Output: []bool{true, true, false, false, true, true, true, true, false, false},
+		},
+	}
+
+	for _, td := range testData {
+		assertions := Assert(td.Checks, response, 150*time.Millisecond)
+		if len(assertions) != len(td.Output) {
+			t.Errorf("%s - expected: %d assertions / output: %d", td.Name, len(td.Output), len(assertions))
+			continue
+		}
+
+		for i, assertion := range assertions {
+			if assertion.Passed != td.Output[i] {
+				t.Errorf("%s - %s expected: %t / output: %t (%s)", td.Name, assertion.Name, td.Output[i], assertion.Passed, assertion.Actual)
+			}
+		}
+	}
+}
+
+func TestAssertDNS(t *testing.T) {
+	result := schema.DNSResult{
+		Name:       "example.com",
+		RecordType: "MX",
+		Rcode:      "NOERROR",
+		Answers: []schema.DNSAnswer{
+			{Type: "MX", Value: "10 mail.example.com.", TTL: 300},
+			{Type: "MX", Value: "20 backup.example.com.", TTL: 30},
+		},
+	}
+
+	testData := []struct {
+		Name   string
+		Option schema.DNSOption
+		Output []bool
+	}{
+		{
+			Name:   "exists",
+			Option: schema.DNSOption{},
+			Output: []bool{true},
+		},
+		{
+			Name:   "should not exist",
+			Option: schema.DNSOption{Exists: aws.Bool(false)},
+			Output: []bool{false},
+		},
+		{
+			Name: "answers and ttl",
+			Option: schema.DNSOption{
+				Expected: []string{"10 MAIL.example.com", "30 other.example.com"},
+				MinTTL:   aws.Int(60),
+			},
+			Output: []bool{true, true, false, true, false},
+		},
+	}
+
+	for _, td := range testData {
+		assertions := AssertDNS(td.Option, result)
+		if len(assertions) != len(td.Output) {
+			t.Errorf("%s - expected: %d assertions / output: %d", td.Name, len(td.Output), len(assertions))
+			continue
+		}
+
+		for i, assertion := range assertions {
+			if assertion.Passed != td.Output[i] {
+				t.Errorf("%s - %s expected: %t / output: %t (%s)", td.Name, assertion.Name, td.Output[i], assertion.Passed, assertion.Actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+var (
+	// variablePattern matches variable reference like `{{ token }}`
+	variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+	// variableName matches valid name of variable
+	variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Extract returns the value of extraction from response
+func Extract(extraction schema.Extraction, response schema.Response) (string, error) {
+	switch {
+	case extraction.JSONPath != nil:
+		return GetJSONPath(*extraction.JSONPath, response.Body)
+	case extraction.Regex != nil:
+		re, err := regexp.Compile(*extraction.Regex)
+		if err != nil {
+			return "", err
+		}
+
+		match := re.FindSubmatch(response.Body)
+		if match == nil {
+			return "", fmt.Errorf("body does not match /%s/", *extraction.Regex)
+		}
+
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	case extraction.Header != nil:
+		values := http.Header(response.Header).Values(*extraction.Header)
+		if len(values) == 0 {
+			return "", fmt.Errorf("header does not exist: %s", *extraction.Header)
+		}
+
+		return values[0], nil
+	}
+
+	return "", errors.New("json_path, regex or header of extraction is required")
+}
+
+// ExpandVariables replaces variable references in s with values
+// References to unknown variables are left as they are.
+func ExpandVariables(s string, variables map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variablePattern.FindStringSubmatch(ref)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+
+		return ref
+	})
+}
+
+// Variables returns names of variables referenced in s
+func Variables(s string) []string {
+	var names []string
+	for _, match := range variablePattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// ValidateSteps checks steps and whether every variable is extracted before it is used
+func ValidateSteps(steps []schema.Step) error {
+	extracted := map[string]bool{}
+	for i, step := range steps {
+		name := aws.StringValue(step.Name)
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		references := Variables(aws.StringValue(step.Path))
+		for _, m := range []map[string]string{step.Header, step.Body, step.Query} {
+			for k, v := range m {
+				references = append(references, Variables(k+v)...)
+			}
+		}
+
+		if step.Payload != nil {
+			normalized := *step.Payload
+			normalized.JSON = tools.NormalizeYAML(normalized.JSON)
+
+			payload, err := json.Marshal(normalized)
+			if err != nil {
+				return err
+			}
+			references = append(references, Variables(string(payload))...)
+		}
+
+		for _, reference := range references {
+			if !extracted[reference] {
+				return fmt.Errorf("variable is not extracted before step %s: %s", name, reference)
+			}
+		}
+
+		if err := Validate(step.Checks); err != nil {
+			return fmt.Errorf("step %s: %s", name, err.Error())
+		}
+
+		for _, extraction := range step.Extract {
+			if extraction.Name == nil || !variableName.MatchString(*extraction.Name) {
+				return fmt.Errorf("name of extraction in step %s is not correct: %s", name, aws.StringValue(extraction.Name))
+			}
+
+			count := 0
+			for _, source := range []*string{extraction.JSONPath, extraction.Regex, extraction.Header} {
+				if source != nil {
+					count++
+				}
+			}
+			if count != 1 {
+				return fmt.Errorf("one of json_path, regex and header is required for extraction: %s", *extraction.Name)
+			}
+
+			if extraction.JSONPath != nil {
+				if _, err := jsonpath.New(*extraction.JSONPath); err != nil {
+					return fmt.Errorf("json_path of extraction is not correct: %s", err.Error())
+				}
+			}
+
+			if extraction.Regex != nil {
+				if _, err := regexp.Compile(*extraction.Regex); err != nil {
+					return fmt.Errorf("regex of extraction is not correct: %s", err.Error())
+				}
+			}
+
+			extracted[*extraction.Name] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestExtract(t *testing.T) {
+	response := schema.Response{
+		Header: map[string][]string{"X-Request-Id": {"abc"}},
+		Body:   []byte(`{"token":"t0k3n","user":{"id":42}}`),
+	}
+
+	testData := []struct {
+		Extraction schema.Extraction
+		Output     string
+		Error      bool
+	}{
+		{Extraction: schema.Extraction{JSONPath: aws.String("$.token")}, Output: "t0k3n"},
+		{Extraction: schema.Extraction{JSONPath: aws.String("$.user.id")}, Output: "42"},
+		{Extraction: schema.Extraction{JSONPath: aws.String("$.missing")}, Error: true},
+		{Extraction: schema.Extraction{Regex: aws.String(`"token":"(\w+)"`)}, Output: "t0k3n"},
+		{Extraction: schema.Extraction{Regex: aws.String(`t0k\d`)}, Output: "t0k3"},
+		{Extraction: schema.Extraction{Regex: aws.String(`nothing`)}, Error: true},
+		{Extraction: schema.Extraction{Header: aws.String("x-request-id")}, Output: "abc"},
+		{Extraction: schema.Extraction{Header: aws.String("X-Missing")}, Error: true},
+		{Extraction: schema.Extraction{}, Error: true},
+	}
+
+	for _, test := range testData {
+		output, err := Extract(test.Extraction, response)
+		if test.Error != (err != nil) {
+			t.Errorf("error expected: %v, got: %v", test.Error, err)
+		}
+
+		if output != test.Output {
+			t.Errorf("expected: %s, got: %s", test.Output, output)
+		}
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	variables := map[string]string{"token": "t0k3n", "id": "42"}
+
+	testData := []struct {
+		Input  string
+		Output string
+	}{
+		{Input: "/orders/{{ id }}", Output: "/orders/42"},
+		{Input: "Bearer {{token}}", Output: "Bearer t0k3n"},
+		{Input: "{{ id }}-{{ id }}", Output: "42-42"},
+		{Input: "{{ unknown }}", Output: "{{ unknown }}"},
+		{Input: "/health", Output: "/health"},
+	}
+
+	for _, test := range testData {
+		if output := ExpandVariables(test.Input, variables); output != test.Output {
+			t.Errorf("expected: %s, got: %s", test.Output, output)
+		}
+	}
+}
+
+func TestValidateSteps(t *testing.T) {
+	testData := []struct {
+		Steps []schema.Step
+		Error bool
+	}{
+		{
+			Steps: []schema.Step{
+				{Path: aws.String("/login"), Extract: []schema.Extraction{{Name: aws.String("token"), JSONPath: aws.String("$.token")}}},
+				{Path: aws.String("/me"), Header: map[string]string{"Authorization": "Bearer {{ token }}"}},
+			},
+		},
+		{
+			Steps: []schema.Step{
+				{Path: aws.String("/orders/{{ id }}")},
+			},
+			Error: true,
+		},
+		{
+			Steps: []schema.Step{
+				{Path: aws.String("/login"), Extract: []schema.Extraction{{Name: aws.String("token"), JSONPath: aws.String("$.token"), Header: aws.String("X-Token")}}},
+			},
+			Error: true,
+		},
+		{
+			Steps: []schema.Step{
+				{Path: aws.String("/login"), Extract: []schema.Extraction{{Name: aws.String("my-token"), Header: aws.String("X-Token")}}},
+			},
+			Error: true,
+		},
+	}
+
+	for _, test := range testData {
+		if err := ValidateSteps(test.Steps); test.Error != (err != nil) {
+			t.Errorf("error expected: %v, got: %v", test.Error, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// wwwPrefix is the prefix of www subdomain
+const wwwPrefix = "www."
+
+// AssertRedirect checks the final URL of redirects which started from start
+func AssertRedirect(option schema.RedirectOption, start, final string) []schema.Assertion {
+	var assertions []schema.Assertion
+
+	startURL, err := url.Parse(start)
+	if err != nil {
+		startURL = &url.URL{}
+	}
+
+	finalURL, err := url.Parse(final)
+	if err != nil {
+		finalURL = &url.URL{}
+	}
+
+	if option.FinalURL != nil {
+		assertions = append(assertions, schema.Assertion{
+			Name:     "final url",
+			Expected: *option.FinalURL,
+			Actual:   final,
+			Passed:   SameURL(*option.FinalURL, final),
+		})
+	}
+
+	if option.HTTPS != nil {
+		expected := startURL.Scheme
+		if *option.HTTPS {
+			expected = "https"
+		}
+
+		assertions = append(assertions, schema.Assertion{
+			Name:     "https redirect",
+			Expected: fmt.Sprintf("scheme %s", expected),
+			Actual:   fmt.Sprintf("scheme %s", finalURL.Scheme),
+			Passed:   finalURL.Scheme == expected,
+		})
+	}
+
+	if option.WWW != nil {
+		apex := strings.TrimPrefix(strings.ToLower(startURL.Hostname()), wwwPrefix)
+		host := strings.ToLower(finalURL.Hostname())
+
+		expected := apex
+		if *option.WWW {
+			expected = wwwPrefix + apex
+		}
+
+		assertions = append(assertions, schema.Assertion{
+			Name:     "www redirect",
+			Expected: fmt.Sprintf("host %s", expected),
+			Actual:   fmt.Sprintf("host %s", host),
+			Passed:   host == expected,
+		})
+	}
+
+	return assertions
+}
+
+// SameURL returns whether two URLs are same except empty path and case of scheme and host
+func SameURL(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return a == b
+	}
+
+	ub, err := url.Parse(b)
+	if err != nil {
+		return a == b
+	}
+
+	for _, u := range []*url.URL{ua, ub} {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		if len(u.Path) == 0 {
+			u.Path = "/"
+		}
+	}
+
+	return ua.String() == ub.String()
+}
+
+// ValidateRedirect checks whether redirect option is well-formed
+func ValidateRedirect(option *schema.RedirectOption) error {
+	if option == nil {
+		return nil
+	}
+
+	if option.MaxHops != nil && *option.MaxHops <= 0 {
+		return fmt.Errorf("max_hops of follow_redirects should be positive: %d", *option.MaxHops)
+	}
+
+	if option.FinalURL != nil {
+		u, err := url.Parse(*option.FinalURL)
+		if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return fmt.Errorf("final_url of follow_redirects should be an absolute URL: %s", *option.FinalURL)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestAssertRedirect(t *testing.T) {
+	testData := []struct {
+		Name   string
+		Option schema.RedirectOption
+		Start  string
+		Final  string
+		Output []bool
+	}{
+		{
+			Name:   "final url without path",
+			Option: schema.RedirectOption{FinalURL: aws.String("https://WWW.example.com")},
+			Start:  "http://example.com",
+			Final:  "https://www.example.com/",
+			Output: []bool{true},
+		},
+		{
+			Name:   "http to https and apex to www",
+			Option: schema.RedirectOption{HTTPS: aws.Bool(true), WWW: aws.Bool(true)},
+			Start:  "http://example.com/login",
+			Final:  "https://www.example.com/login",
+			Output: []bool{true, true},
+		},
+		{
+			Name:   "no https redirect",
+			Option: schema.RedirectOption{HTTPS: aws.Bool(true)},
+			Start:  "http://example.com",
+			Final:  "http://www.example.com/",
+			Output: []bool{false},
+		},
+		{
+			Name:   "www to apex",
+			Option: schema.RedirectOption{HTTPS: aws.Bool(false), WWW: aws.Bool(false)},
+			Start:  "https://www.example.com",
+			Final:  "https://example.com/",
+			Output: []bool{true, true},
+		},
+		{
+			Name:   "apex is not redirected to www",
+			Option: schema.RedirectOption{WWW: aws.Bool(true)},
+			Start:  "https://example.com",
+			Final:  "https://example.com/",
+			Output: []bool{false},
+		},
+	}
+
+	for _, td := range testData {
+		assertions := AssertRedirect(td.Option, td.Start, td.Final)
+		if len(assertions) != len(td.Output) {
+			t.Errorf("%s - expected: %d assertions / output: %d", td.Name, len(td.Output), len(assertions))
+			continue
+		}
+
+		for i, assertion := range assertions {
+			if assertion.Passed != td.Output[i] {
+				t.Errorf("%s - %s expected: %t / output: %t (%s)", td.Name, assertion.Name, td.Output[i], assertion.Passed, assertion.Actual)
+			}
+		}
+	}
+}
@@ -152,3 +152,44 @@ func (i IAM) DetachIAMPolicy(name string) error {
 
 	return nil
 }
+
+// PutRolePolicy puts inline policy to role
+func (i IAM) PutRolePolicy(roleName, policyName, document string) error {
+	input := &iam.PutRolePolicyInput{
+		PolicyDocument: aws.String(document),
+		PolicyName:     aws.String(policyName),
+		RoleName:       aws.String(roleName),
+	}
+
+	_, err := i.Client.PutRolePolicy(input)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Inline policy %s is put to IAM role: %s", policyName, roleName)
+
+	return nil
+}
+
+// DeleteRolePolicy deletes inline policy of role
+func (i IAM) DeleteRolePolicy(roleName, policyName string) error {
+	input := &iam.DeleteRolePolicyInput{
+		PolicyName: aws.String(policyName),
+		RoleName:   aws.String(roleName),
+	}
+
+	_, err := i.Client.DeleteRolePolicy(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			if aerr.Code() == iam.ErrCodeNoSuchEntityException {
+				logrus.Debugf("Inline policy %s does not exist in role: %s", policyName, roleName)
+				return nil
+			}
+		}
+		return err
+	}
+
+	logrus.Infof("Inline policy %s is deleted from IAM role: %s", policyName, roleName)
+
+	return nil
+}
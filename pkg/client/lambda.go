@@ -17,6 +17,8 @@ limitations under the License.
 package client
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
@@ -165,8 +167,8 @@ func (l Lambda) UpdateTemplate(config config.Config) error {
 	return nil
 }
 
-// Trigger will invoke lambda function
-func (l *Lambda) Trigger(region *string, template *string, payload []byte, internal bool) error {
+// Trigger will invoke lambda function and return the response payload
+func (l *Lambda) Trigger(region *string, template *string, payload []byte, internal bool) ([]byte, error) {
 	functionName := tools.GenerateNewWorkerName(region, template, constants.WorkerMode, internal)
 
 	input := &lambda.InvokeInput{
@@ -174,14 +176,18 @@ func (l *Lambda) Trigger(region *string, template *string, payload []byte, inter
 		Payload:      payload,
 	}
 
-	_, err := l.Client.Invoke(input)
+	result, err := l.Client.Invoke(input)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if result.FunctionError != nil {
+		return nil, fmt.Errorf("%s returns %s error: %s", functionName, *result.FunctionError, string(result.Payload))
 	}
 
 	logrus.Infof("Lambda is successfully invoked: %s", functionName)
 
-	return nil
+	return result.Payload, nil
 }
 
 // GetFunctionARN gets function ARN
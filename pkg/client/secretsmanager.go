@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+type SecretsManager struct {
+	Client *secretsmanager.SecretsManager
+}
+
+// NewSecretsManagerClient creates Secrets Manager client
+func NewSecretsManagerClient(region string) *SecretsManager {
+	session := GetAwsSession()
+	return &SecretsManager{
+		Client: GetSecretsManagerClientFn(session, region, nil),
+	}
+}
+
+// GetSecretsManagerClientFn creates a new AWS Secrets Manager client
+func GetSecretsManagerClientFn(sess client.ConfigProvider, region string, creds *credentials.Credentials) *secretsmanager.SecretsManager {
+	if creds == nil {
+		return secretsmanager.New(sess, &aws.Config{Region: aws.String(region)})
+	}
+	return secretsmanager.New(sess, &aws.Config{Region: aws.String(region), Credentials: creds})
+}
+
+// GetSecretString returns string value of the current version of secret
+func (s SecretsManager) GetSecretString(ctx context.Context, id string) (string, error) {
+	input := &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	}
+
+	result, err := s.Client.GetSecretValueWithContext(ctx, input)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	if result.SecretString == nil {
+		return constants.EmptyString, fmt.Errorf("secret has no string value: %s", id)
+	}
+
+	return *result.SecretString, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/ssm"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+type SSM struct {
+	Client *ssm.SSM
+}
+
+// NewSSMClient creates SSM client
+func NewSSMClient(region string) *SSM {
+	session := GetAwsSession()
+	return &SSM{
+		Client: GetSSMClientFn(session, region, nil),
+	}
+}
+
+// GetSSMClientFn creates a new AWS SSM client
+func GetSSMClientFn(sess client.ConfigProvider, region string, creds *credentials.Credentials) *ssm.SSM {
+	if creds == nil {
+		return ssm.New(sess, &aws.Config{Region: aws.String(region)})
+	}
+	return ssm.New(sess, &aws.Config{Region: aws.String(region), Credentials: creds})
+}
+
+// GetParameter returns decrypted value of parameter
+func (s SSM) GetParameter(ctx context.Context, name string) (string, error) {
+	input := &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	}
+
+	result, err := s.Client.GetParameterWithContext(ctx, input)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	return aws.StringValue(result.Parameter.Value), nil
+}
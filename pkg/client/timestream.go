@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
@@ -75,8 +76,6 @@ func GetTimeStreamWriteClientFn(sess client.ConfigProvider, region string, creds
 
 // WriteData writes data to time series database
 func (t *TimeStream) WriteData(databaseName, tableName, region, protocol string, result schema.Result) error {
-	now := time.Now()
-	currentTimeInSeconds := now.Unix()
 	dimensions := []*timestreamwrite.Dimension{
 		{
 			Name:  aws.String("target"),
@@ -87,75 +86,444 @@ func (t *TimeStream) WriteData(databaseName, tableName, region, protocol string,
 			Value: aws.String(region),
 		},
 	}
-	inputTime := aws.String(strconv.FormatInt(currentTimeInSeconds, 10))
-	timeUnit := aws.String("SECONDS")
 
-	input := &timestreamwrite.WriteRecordsInput{
-		DatabaseName: aws.String(databaseName),
-		TableName:    aws.String(tableName),
-		Records: []*timestreamwrite.Record{
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("status_code"),
-				MeasureValue:     aws.String(tools.IntToString(result.Response.StatusCode)),
-				MeasureValueType: aws.String("BIGINT"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("dns_lookup"),
-				MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.DNSLookup.Milliseconds())),
-				MeasureValueType: aws.String("DOUBLE"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("tcp_connection"),
-				MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.TCPConnection.Milliseconds())),
-				MeasureValueType: aws.String("DOUBLE"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("server_processing"),
-				MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.ServerProcessing.Milliseconds())),
-				MeasureValueType: aws.String("DOUBLE"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("content_transfer"),
-				MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.ContentTransfer.Milliseconds())),
-				MeasureValueType: aws.String("DOUBLE"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
-			{
-				Dimensions:       dimensions,
-				MeasureName:      aws.String("total"),
-				MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.Total.Milliseconds())),
-				MeasureValueType: aws.String("DOUBLE"),
-				Time:             inputTime,
-				TimeUnit:         timeUnit,
-			},
+	return t.WriteRecords(databaseName, tableName, tracingRecords(dimensions, protocol, result))
+}
+
+// WriteStepData writes result of a step in transaction to time series database
+// Target is the base URL of transaction so that steps are grouped with it.
+func (t *TimeStream) WriteStepData(databaseName, tableName, region, protocol, target string, step schema.StepResult) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(target),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("step"),
+			Value: aws.String(step.Name),
+		},
+	}
+
+	return t.WriteRecords(databaseName, tableName, tracingRecords(dimensions, protocol, step.Result))
+}
+
+// WriteBackendData writes result of a backend IP of target to time series database
+func (t *TimeStream) WriteBackendData(databaseName, tableName, region, protocol, target string, backend schema.BackendResult) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(target),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("ip"),
+			Value: aws.String(backend.IP),
 		},
 	}
 
+	return t.WriteRecords(databaseName, tableName, tracingRecords(dimensions, protocol, backend.Result))
+}
+
+// tracingRecords returns records of request tracing result
+func tracingRecords(dimensions []*timestreamwrite.Dimension, protocol string, result schema.Result) []*timestreamwrite.Record {
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "status_code", tools.IntToString(result.Response.StatusCode), "BIGINT"),
+		newRecord(dimensions, "failed", strconv.FormatBool(result.Failed()), "BOOLEAN"),
+		newRecord(dimensions, "dns_lookup", tools.Int64ToString(result.TracingData.DNSLookup.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tcp_connection", tools.Int64ToString(result.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "server_processing", tools.Int64ToString(result.TracingData.ServerProcessing.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "content_transfer", tools.Int64ToString(result.TracingData.ContentTransfer.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "total", tools.Int64ToString(result.TracingData.Total.Milliseconds()), "DOUBLE"),
+	}
+
 	if protocol == constants.HTTPS {
-		input.Records = append(input.Records, &timestreamwrite.Record{
-			Dimensions:       dimensions,
-			MeasureName:      aws.String("tls_handshaking"),
-			MeasureValue:     aws.String(tools.Int64ToString(result.TracingData.TLSHandShacking.Milliseconds())),
-			MeasureValueType: aws.String("DOUBLE"),
-			Time:             inputTime,
-			TimeUnit:         timeUnit,
+		records = append(records, newRecord(dimensions, "tls_handshaking", tools.Int64ToString(result.TracingData.TLSHandShacking.Milliseconds()), "DOUBLE"))
+	}
+
+	if p := result.Response.Protocol; p != nil {
+		records = append(records, newRecord(dimensions, "http_version", p.HTTPVersion, "VARCHAR"))
+		if len(p.TLSVersion) > 0 {
+			records = append(records,
+				newRecord(dimensions, "tls_version", p.TLSVersion, "VARCHAR"),
+				newRecord(dimensions, "cipher_suite", p.CipherSuite, "VARCHAR"),
+				newRecord(dimensions, "session_resumed", strconv.FormatBool(p.SessionResumed), "BOOLEAN"),
+				newRecord(dimensions, "ocsp_stapled", strconv.FormatBool(p.OCSPStapled), "BOOLEAN"),
+			)
+		}
+		if len(p.ALPN) > 0 {
+			records = append(records, newRecord(dimensions, "alpn", p.ALPN, "VARCHAR"))
+		}
+	}
+
+	if cert := result.Certificate; cert != nil {
+		records = append(records,
+			newRecord(dimensions, "cert_days_until_expiry", tools.IntToString(cert.DaysUntilExpiry), "BIGINT"),
+			newRecord(dimensions, "cert_chain_verified", strconv.FormatBool(cert.ChainVerified), "BOOLEAN"),
+			newRecord(dimensions, "cert_not_after", cert.NotAfter.Format(time.RFC3339), "VARCHAR"),
+			newRecord(dimensions, "cert_serial", cert.SerialNumber, "VARCHAR"),
+		)
+	}
+
+	if len(result.Redirects) > 0 {
+		records = append(records,
+			newRecord(dimensions, "redirects", tools.IntToString(len(result.Redirects)-1), "BIGINT"),
+			newRecord(dimensions, "final_url", result.Redirects[len(result.Redirects)-1].URL, "VARCHAR"),
+		)
+	}
+
+	records = append(records, newRecord(dimensions, "conn_reused", strconv.FormatBool(result.TracingData.Reused), "BOOLEAN"))
+
+	if warm := result.Warm; warm != nil {
+		records = append(records,
+			newRecord(dimensions, "warm_status_code", tools.IntToString(warm.Response.StatusCode), "BIGINT"),
+			newRecord(dimensions, "warm_conn_reused", strconv.FormatBool(warm.TracingData.Reused), "BOOLEAN"),
+			newRecord(dimensions, "warm_conn_was_idle", strconv.FormatBool(warm.TracingData.WasIdle), "BOOLEAN"),
+			newRecord(dimensions, "warm_tcp_connection", tools.Int64ToString(warm.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+			newRecord(dimensions, "warm_server_processing", tools.Int64ToString(warm.TracingData.ServerProcessing.Milliseconds()), "DOUBLE"),
+			newRecord(dimensions, "warm_content_transfer", tools.Int64ToString(warm.TracingData.ContentTransfer.Milliseconds()), "DOUBLE"),
+			newRecord(dimensions, "warm_total", tools.Int64ToString(warm.TracingData.Total.Milliseconds()), "DOUBLE"),
+		)
+		if protocol == constants.HTTPS {
+			records = append(records, newRecord(dimensions, "warm_tls_handshaking", tools.Int64ToString(warm.TracingData.TLSHandShacking.Milliseconds()), "DOUBLE"))
+		}
+	}
+
+	if samples := result.Samples; samples != nil {
+		records = append(records,
+			newRecord(dimensions, "samples", tools.IntToString(samples.Count), "BIGINT"),
+			newRecord(dimensions, "failed_samples", tools.IntToString(samples.Failed), "BIGINT"),
+		)
+		records = append(records, phaseRecords(dimensions, "dns_lookup", samples.DNSLookup)...)
+		records = append(records, phaseRecords(dimensions, "tcp_connection", samples.TCPConnection)...)
+		if protocol == constants.HTTPS {
+			records = append(records, phaseRecords(dimensions, "tls_handshaking", samples.TLSHandShacking)...)
+		}
+		records = append(records, phaseRecords(dimensions, "server_processing", samples.ServerProcessing)...)
+		records = append(records, phaseRecords(dimensions, "content_transfer", samples.ContentTransfer)...)
+		records = append(records, phaseRecords(dimensions, "total", samples.Total)...)
+	}
+
+	if len(result.Attempts) > 0 {
+		records = append(records,
+			newRecord(dimensions, "attempts", tools.IntToString(len(result.Attempts)), "BIGINT"),
+			newRecord(dimensions, "succeeded_on_retry", strconv.FormatBool(result.SucceededOnRetry()), "BOOLEAN"),
+		)
+	}
+
+	if len(result.Backends) > 0 {
+		failedBackends := 0
+		for _, backend := range result.Backends {
+			if backend.Result.Failed() {
+				failedBackends++
+			}
+		}
+
+		records = append(records,
+			newRecord(dimensions, "backends", tools.IntToString(len(result.Backends)), "BIGINT"),
+			newRecord(dimensions, "failed_backends", tools.IntToString(failedBackends), "BIGINT"),
+		)
+	}
+
+	if result.Failure != nil {
+		records = append(records,
+			newRecord(dimensions, "failure_phase", result.Failure.Phase, "VARCHAR"),
+			newRecord(dimensions, "failure_reason", result.Failure.Reason, "VARCHAR"),
+		)
+	}
+
+	return records
+}
+
+// phaseRecords returns records of statistics of a phase like `total_p95`
+func phaseRecords(dimensions []*timestreamwrite.Dimension, phase string, statistics schema.PhaseStatistics) []*timestreamwrite.Record {
+	return []*timestreamwrite.Record{
+		newRecord(dimensions, phase+"_min", tools.Int64ToString(statistics.Min.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, phase+"_median", tools.Int64ToString(statistics.Median.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, phase+"_p95", tools.Int64ToString(statistics.P95.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, phase+"_max", tools.Int64ToString(statistics.Max.Milliseconds()), "DOUBLE"),
+	}
+}
+
+// WriteRedirectData writes a hop of redirects to time series database
+// Hop is the position of request in redirects starting from 1.
+func (t *TimeStream) WriteRedirectData(databaseName, tableName, region, target string, hop int, data schema.RedirectHop) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(target),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("hop"),
+			Value: aws.String(tools.IntToString(hop)),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "url", data.URL, "VARCHAR"),
+		newRecord(dimensions, "status_code", tools.IntToString(data.StatusCode), "BIGINT"),
+		newRecord(dimensions, "dns_lookup", tools.Int64ToString(data.TracingData.DNSLookup.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tcp_connection", tools.Int64ToString(data.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tls_handshaking", tools.Int64ToString(data.TracingData.TLSHandShacking.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "server_processing", tools.Int64ToString(data.TracingData.ServerProcessing.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "total", tools.Int64ToString(data.TracingData.Total.Milliseconds()), "DOUBLE"),
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WritePingData writes ping statistics to time series database
+func (t *TimeStream) WritePingData(databaseName, tableName, region string, stats schema.PingStatistics) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(stats.Addr),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.PingType),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "packets_sent", tools.IntToString(stats.PacketsSent), "BIGINT"),
+		newRecord(dimensions, "packets_recv", tools.IntToString(stats.PacketsRecv), "BIGINT"),
+		newRecord(dimensions, "packet_loss", strconv.FormatFloat(stats.PacketLoss, 'f', -1, 64), "DOUBLE"),
+		newRecord(dimensions, "min_rtt", tools.Int64ToString(stats.MinRtt.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "avg_rtt", tools.Int64ToString(stats.AvgRtt.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "max_rtt", tools.Int64ToString(stats.MaxRtt.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "stddev_rtt", tools.Int64ToString(stats.StdDevRtt.Milliseconds()), "DOUBLE"),
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteDNSData writes DNS resolution result to time series database
+func (t *TimeStream) WriteDNSData(databaseName, tableName, region string, result schema.Result) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(result.DNS.Name),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.DNSType),
+		},
+		{
+			Name:  aws.String("record_type"),
+			Value: aws.String(result.DNS.RecordType),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "resolution_time", tools.Int64ToString(result.DNS.ResolutionTime.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "answers", tools.IntToString(len(result.DNS.Answers)), "BIGINT"),
+		newRecord(dimensions, "failed", strconv.FormatBool(result.Failed()), "BOOLEAN"),
+	}
+
+	if len(result.DNS.Rcode) > 0 {
+		records = append(records, newRecord(dimensions, "rcode", result.DNS.Rcode, "VARCHAR"))
+	}
+
+	if result.Failure != nil {
+		records = append(records, newRecord(dimensions, "failure_reason", result.Failure.Reason, "VARCHAR"))
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteTCPData writes TCP connection result to time series database
+func (t *TimeStream) WriteTCPData(databaseName, tableName, region string, result schema.Result) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(result.TCP.Address),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.TCPType),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "dns_lookup", tools.Int64ToString(result.TracingData.DNSLookup.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tcp_connection", tools.Int64ToString(result.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "first_byte", tools.Int64ToString(result.TracingData.ServerProcessing.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "total", tools.Int64ToString(result.TracingData.Total.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "failed", strconv.FormatBool(result.Failed()), "BOOLEAN"),
+	}
+
+	if result.Failure != nil {
+		records = append(records,
+			newRecord(dimensions, "failure_phase", result.Failure.Phase, "VARCHAR"),
+			newRecord(dimensions, "failure_reason", result.Failure.Reason, "VARCHAR"),
+		)
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteGRPCData writes gRPC health checking result to time series database
+func (t *TimeStream) WriteGRPCData(databaseName, tableName, region string, result schema.Result) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(result.GRPC.Address),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.GRPCType),
+		},
+	}
+
+	if len(result.GRPC.Service) > 0 {
+		dimensions = append(dimensions, &timestreamwrite.Dimension{
+			Name:  aws.String("service"),
+			Value: aws.String(result.GRPC.Service),
 		})
 	}
 
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "dns_lookup", tools.Int64ToString(result.TracingData.DNSLookup.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tcp_connection", tools.Int64ToString(result.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "connect", tools.Int64ToString(result.GRPC.ConnectTime.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "rpc_latency", tools.Int64ToString(result.GRPC.RPCLatency.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "total", tools.Int64ToString(result.TracingData.Total.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "failed", strconv.FormatBool(result.Failed()), "BOOLEAN"),
+	}
+
+	if len(result.GRPC.Status) > 0 {
+		records = append(records, newRecord(dimensions, "serving_status", result.GRPC.Status, "VARCHAR"))
+	}
+
+	if result.Failure != nil {
+		records = append(records,
+			newRecord(dimensions, "failure_phase", result.Failure.Phase, "VARCHAR"),
+			newRecord(dimensions, "failure_reason", result.Failure.Reason, "VARCHAR"),
+		)
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteWebSocketData writes WebSocket probe result to time series database
+func (t *TimeStream) WriteWebSocketData(databaseName, tableName, region string, result schema.Result) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(result.WebSocket.URL),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.WebSocketType),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "dns_lookup", tools.Int64ToString(result.TracingData.DNSLookup.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tcp_connection", tools.Int64ToString(result.TracingData.TCPConnection.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "tls_handshaking", tools.Int64ToString(result.TracingData.TLSHandShacking.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "upgrade", tools.Int64ToString(result.WebSocket.UpgradeLatency.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "total", tools.Int64ToString(result.TracingData.Total.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "failed", strconv.FormatBool(result.Failed()), "BOOLEAN"),
+	}
+
+	if result.WebSocket.RoundTrip > 0 {
+		records = append(records, newRecord(dimensions, "round_trip", tools.Int64ToString(result.WebSocket.RoundTrip.Milliseconds()), "DOUBLE"))
+	}
+
+	if result.Failure != nil {
+		records = append(records,
+			newRecord(dimensions, "failure_phase", result.Failure.Phase, "VARCHAR"),
+			newRecord(dimensions, "failure_reason", result.Failure.Reason, "VARCHAR"),
+		)
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteLoadData writes load test metrics to time series database
+func (t *TimeStream) WriteLoadData(databaseName, tableName, region string, metrics schema.LoadMetrics) error {
+	dimensions := []*timestreamwrite.Dimension{
+		{
+			Name:  aws.String("target"),
+			Value: aws.String(metrics.URL),
+		},
+		{
+			Name:  aws.String("region"),
+			Value: aws.String(region),
+		},
+		{
+			Name:  aws.String("type"),
+			Value: aws.String(constants.LoadType),
+		},
+	}
+
+	records := []*timestreamwrite.Record{
+		newRecord(dimensions, "requests", strconv.FormatUint(metrics.Requests, 10), "BIGINT"),
+		newRecord(dimensions, "rate", strconv.FormatFloat(metrics.Rate, 'f', -1, 64), "DOUBLE"),
+		newRecord(dimensions, "throughput", strconv.FormatFloat(metrics.Throughput, 'f', -1, 64), "DOUBLE"),
+		newRecord(dimensions, "success", strconv.FormatFloat(metrics.Success, 'f', -1, 64), "DOUBLE"),
+		newRecord(dimensions, "latency_mean", tools.Int64ToString(metrics.Latencies.Mean.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "latency_p50", tools.Int64ToString(metrics.Latencies.P50.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "latency_p90", tools.Int64ToString(metrics.Latencies.P90.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "latency_p95", tools.Int64ToString(metrics.Latencies.P95.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "latency_p99", tools.Int64ToString(metrics.Latencies.P99.Milliseconds()), "DOUBLE"),
+		newRecord(dimensions, "latency_max", tools.Int64ToString(metrics.Latencies.Max.Milliseconds()), "DOUBLE"),
+	}
+
+	for code, count := range metrics.StatusCodes {
+		records = append(records, newRecord(dimensions, fmt.Sprintf("status_%s", code), tools.IntToString(count), "BIGINT"))
+	}
+
+	return t.WriteRecords(databaseName, tableName, records)
+}
+
+// WriteRecords writes records to time series database
+func (t *TimeStream) WriteRecords(databaseName, tableName string, records []*timestreamwrite.Record) error {
+	now := time.Now()
+	inputTime := aws.String(strconv.FormatInt(now.Unix(), 10))
+	for _, record := range records {
+		record.Time = inputTime
+		record.TimeUnit = aws.String("SECONDS")
+	}
+
+	input := &timestreamwrite.WriteRecordsInput{
+		DatabaseName: aws.String(databaseName),
+		TableName:    aws.String(tableName),
+		Records:      records,
+	}
+
 	_, err := t.WriteClient.WriteRecords(input)
 
 	if err != nil {
@@ -166,3 +534,13 @@ func (t *TimeStream) WriteData(databaseName, tableName, region, protocol string,
 
 	return nil
 }
+
+// newRecord creates a new record for measure
+func newRecord(dimensions []*timestreamwrite.Dimension, name, value, valueType string) *timestreamwrite.Record {
+	return &timestreamwrite.Record{
+		Dimensions:       dimensions,
+		MeasureName:      aws.String(name),
+		MeasureValue:     aws.String(value),
+		MeasureValueType: aws.String(valueType),
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestTracingRecordsAttempts(t *testing.T) {
+	result := schema.Result{
+		Response: schema.Response{StatusCode: 200},
+		Attempts: []schema.Attempt{
+			{Number: 1, Passed: false, StatusCode: 503},
+			{Number: 2, Passed: true, StatusCode: 200},
+		},
+	}
+
+	measures := map[string]string{}
+	for _, record := range tracingRecords(nil, constants.HTTP, result) {
+		measures[aws.StringValue(record.MeasureName)] = aws.StringValue(record.MeasureValue)
+	}
+
+	expected := map[string]string{
+		"failed":             "false",
+		"attempts":           "2",
+		"succeeded_on_retry": "true",
+	}
+	for name, value := range expected {
+		if measures[name] != value {
+			t.Errorf("%s - expected: %s, got: %s", name, value, measures[name])
+		}
+	}
+}
@@ -54,6 +54,120 @@ const (
 	// DefaultShooter means default shooter
 	DefaultShooter = "trace"
 
+	// HTTPType is target type for HTTP request tracing
+	HTTPType = "http"
+
+	// PingType is target type for ICMP echo requests
+	PingType = "ping"
+
+	// LoadType is target type for load test
+	LoadType = "load"
+
+	// DNSType is target type for DNS resolution
+	DNSType = "dns"
+
+	// TCPType is target type for raw TCP connection
+	TCPType = "tcp"
+
+	// GRPCType is target type for gRPC health checking
+	GRPCType = "grpc"
+
+	// WebSocketType is target type for WebSocket upgrade and message round-trip
+	WebSocketType = "websocket"
+
+	// DefaultRecordType is default record type of DNS query
+	DefaultRecordType = "A"
+
+	// DefaultDNSPort is default port of nameserver
+	DefaultDNSPort = "53"
+
+	// ResolvConfPath is the path of system resolver configuration
+	ResolvConfPath = "/etc/resolv.conf"
+
+	// DefaultLoadRate is default number of requests per second for load test
+	DefaultLoadRate = 10
+
+	// DefaultLoadDuration is default duration of load test
+	DefaultLoadDuration = 10 * time.Second
+
+	// HistogramMaxLatency is the highest latency tracked by latency histogram
+	HistogramMaxLatency = 10 * time.Minute
+
+	// HistogramSignificantFigures is the number of significant figures of latency histogram
+	HistogramSignificantFigures = 3
+
+	// DefaultMinSuccess is default minimum ratio of successful requests for load test
+	DefaultMinSuccess = float64(1)
+
+	// DefaultStepMethod is method of step when it is not specified
+	DefaultStepMethod = "GET"
+
+	// DefaultMaxRedirects is default maximum number of redirects to follow
+	DefaultMaxRedirects = 10
+
+	// BasicAuth is authentication type of HTTP basic authentication
+	BasicAuth = "basic"
+
+	// BearerAuth is authentication type of static bearer token
+	BearerAuth = "bearer"
+
+	// OAuth2Auth is authentication type of OAuth2 client credentials grant
+	OAuth2Auth = "oauth2"
+
+	// ExecuteAPIService is signing name of API Gateway
+	ExecuteAPIService = "execute-api"
+
+	// LambdaService is signing name of Lambda function URL
+	LambdaService = "lambda"
+
+	// WorkerInvokePolicyName is name of inline policy of worker role for invoking IAM-protected targets
+	WorkerInvokePolicyName = "bigshot-worker-invoke"
+
+	// SSMSource is secret source of SSM Parameter Store
+	SSMSource = "ssm"
+
+	// SecretsManagerSource is secret source of Secrets Manager
+	SecretsManagerSource = "secretsmanager"
+
+	// EnvSource is secret source of environment variables where the check runs
+	EnvSource = "env"
+
+	// MinRedactLength is the shortest secret value which is redacted from results
+	MinRedactLength = 4
+
+	// DefaultTokenLifetime is lifetime of OAuth2 token whose response has no expires_in
+	DefaultTokenLifetime = 5 * time.Minute
+
+	// TokenExpiryDelta is the time before expiry when OAuth2 token is refreshed
+	TokenExpiryDelta = 30 * time.Second
+
+	// JSONContentType is content type of JSON request body
+	JSONContentType = "application/json"
+
+	// FormContentType is content type of form request body
+	FormContentType = "application/x-www-form-urlencoded"
+
+	// RawContentType is content type of raw request body when it is not specified
+	RawContentType = "text/plain"
+
+	// MaxResponseBodySize is the maximum bytes of response body read for checks
+	MaxResponseBodySize = 1 << 20
+
+	// DefaultReportFormat is default format of load test report
+	DefaultReportFormat = "text"
+
+	// DefaultPingCount is default number of ICMP echo requests
+	DefaultPingCount = 3
+
+	// DefaultPingInterval is default interval between ICMP echo requests
+	DefaultPingInterval = 200 * time.Millisecond
+
+	// DefaultMaxPacketLoss is default allowed packet loss in percentage
+	DefaultMaxPacketLoss = float64(100)
+
+	// LocalRegion is the region name used when checks run from this machine
+	LocalRegion = "local"
+
 	// DefaultWorkerDuration means default duration of lambda
 	DefaultWorkerDuration = 1 * time.Second
 
@@ -90,18 +204,39 @@ const (
 	// HTTP means http protocol
 	HTTP = "HTTP"
 
+	// DefaultHTTPPort is default port of http scheme
+	DefaultHTTPPort = "80"
+
+	// DefaultHTTPSPort is default port of https scheme
+	DefaultHTTPSPort = "443"
+
 	// DefaultTimeout is default lambda execution timeout
 	DefaultTimeout = 300
 
 	// DefaultTargetTimeout is default lambda execution timeout
 	DefaultTargetTimeout = 5
 
+	// DefaultRetryBackoff is default seconds to wait before the first retry
+	DefaultRetryBackoff = 1
+
+	// MaxRetries is the maximum number of retries of a check
+	MaxRetries = 5
+
+	// MaxRetryBackoff is the maximum seconds to wait before a retry
+	MaxRetryBackoff = 60
+
+	// MaxSamples is the maximum number of requests in a check
+	MaxSamples = 100
+
 	// DefaultInterval is default synthetics interval
 	DefaultInterval = 300
 
 	// ErrorColor is red color
 	ErrorColor = "#ff0000"
 
+	// WarningColor is orange color
+	WarningColor = "#ffa500"
+
 	// BigShotSlackURLs
 	BigShotSlackURLs = "slack_urls"
 
@@ -113,6 +248,126 @@ const (
 
 	// DefaultServerPort indicates the default server port
 	DefaultServerPort = int64(8765)
+
+	/*
+		Failure classification
+	*/
+	// PhaseDNS is the phase of DNS lookup
+	PhaseDNS = "dns"
+
+	// PhaseTCP is the phase of TCP connection
+	PhaseTCP = "tcp"
+
+	// PhaseTLS is the phase of TLS handshake
+	PhaseTLS = "tls"
+
+	// PhaseFirstByte is the phase of waiting for the first response byte
+	PhaseFirstByte = "first_byte"
+
+	// PhaseContent is the phase of content transfer
+	PhaseContent = "content"
+
+	// PhaseHTTP is the phase of checking response
+	PhaseHTTP = "http"
+
+	// PhaseRPC is the phase of gRPC call
+	PhaseRPC = "rpc"
+
+	// PhaseMessage is the phase of WebSocket message round-trip
+	PhaseMessage = "message"
+
+	// PhaseExtract is the phase of extracting variables from response of step
+	PhaseExtract = "extract"
+
+	// PhaseAuth is the phase of getting credentials before request
+	PhaseAuth = "auth"
+
+	// PhaseRedirect is the phase of following redirects
+	PhaseRedirect = "redirect"
+
+	// ReasonDNSNXDomain means the host does not exist
+	ReasonDNSNXDomain = "dns_nxdomain"
+
+	// ReasonDNSTimeout means DNS lookup is timed out
+	ReasonDNSTimeout = "dns_timeout"
+
+	// ReasonDNSError means DNS lookup failed with other reasons
+	ReasonDNSError = "dns_error"
+
+	// ReasonTCPRefused means connection is refused
+	ReasonTCPRefused = "tcp_refused"
+
+	// ReasonTCPReset means connection is reset by peer
+	ReasonTCPReset = "tcp_reset"
+
+	// ReasonTCPUnreachable means host or network is unreachable
+	ReasonTCPUnreachable = "tcp_unreachable"
+
+	// ReasonTCPTimeout means connection is timed out
+	ReasonTCPTimeout = "tcp_timeout"
+
+	// ReasonTCPError means connection failed with other reasons
+	ReasonTCPError = "tcp_error"
+
+	// ReasonTLSExpiredCert means certificate is expired or not yet valid
+	ReasonTLSExpiredCert = "tls_expired_cert"
+
+	// ReasonTLSUnknownAuthority means certificate is signed by unknown authority
+	ReasonTLSUnknownAuthority = "tls_unknown_authority"
+
+	// ReasonTLSHostnameMismatch means certificate is not valid for the host
+	ReasonTLSHostnameMismatch = "tls_hostname_mismatch"
+
+	// ReasonTLSTimeout means TLS handshake is timed out
+	ReasonTLSTimeout = "tls_timeout"
+
+	// ReasonTLSError means TLS handshake failed with other reasons
+	ReasonTLSError = "tls_error"
+
+	// ReasonTLSConfigError means client certificate or CA bundle of target cannot be loaded
+	ReasonTLSConfigError = "tls_config_error"
+
+	// ReasonTimeoutFirstByte means server did not respond in time
+	ReasonTimeoutFirstByte = "timeout_first_byte"
+
+	// ReasonTimeoutContent means response body is not transferred in time
+	ReasonTimeoutContent = "timeout_content"
+
+	// ReasonContentError means response body cannot be read
+	ReasonContentError = "content_error"
+
+	// ReasonHTTPStatus means status code is not expected
+	ReasonHTTPStatus = "http_status"
+
+	// ReasonCheckFailed means other response checks failed
+	ReasonCheckFailed = "check_failed"
+
+	// ReasonGRPCNotServing means gRPC health status is not SERVING
+	ReasonGRPCNotServing = "grpc_not_serving"
+
+	// ReasonGRPCPrefix is the prefix of reason of gRPC call failure followed by status code like grpc_unavailable
+	ReasonGRPCPrefix = "grpc_"
+
+	// ReasonMessageTimeout means no WebSocket message is received in time
+	ReasonMessageTimeout = "message_timeout"
+
+	// ReasonMessageError means WebSocket message cannot be sent or received
+	ReasonMessageError = "message_error"
+
+	// ReasonExtractFailed means variable cannot be extracted from response of step
+	ReasonExtractFailed = "extract_failed"
+
+	// ReasonTooManyRedirects means redirects did not end within max hops
+	ReasonTooManyRedirects = "too_many_redirects"
+
+	// ReasonRedirectError means location of redirect is not correct
+	ReasonRedirectError = "redirect_error"
+
+	// ReasonAuthFailed means credentials cannot be obtained like failure of OAuth2 token request
+	ReasonAuthFailed = "auth_failed"
+
+	// ReasonUnknown means failure cannot be classified
+	ReasonUnknown = "unknown"
 )
 
 var (
@@ -143,10 +398,68 @@ var (
 	// AWSConfigPath is the file path of aws config
 	AWSConfigPath = HomeDir() + "/.aws/config"
 
+	// AllowedTypes means a list of target types allowed
+	AllowedTypes = []string{
+		HTTPType,
+		PingType,
+		LoadType,
+		DNSType,
+		TCPType,
+		GRPCType,
+		WebSocketType,
+	}
+
+	// AllowedRecordTypes means a list of DNS record types allowed
+	AllowedRecordTypes = []string{
+		"A",
+		"AAAA",
+		"CNAME",
+		"MX",
+		"TXT",
+		"NS",
+		"CAA",
+	}
+
+	// DefaultExpiryThresholds is default days before certificate expiry to send alarm
+	DefaultExpiryThresholds = []int{30, 14, 7}
+
+	// AllowedReportFormats means a list of load test report formats
+	AllowedReportFormats = []string{
+		"text",
+		"json",
+		"html",
+	}
+
+	// AllowedSchemes means a list of URL schemes allowed for target
+	AllowedSchemes = []string{
+		"http",
+		"https",
+		"ws",
+		"wss",
+	}
+
 	// AllowedMethods means a list of methods allowed
 	AllowedMethods = []string{
 		"GET",
 		"POST",
+		"PUT",
+		"PATCH",
+		"DELETE",
+		"HEAD",
+		"OPTIONS",
+	}
+
+	// MethodsWithoutBody means a list of methods which cannot have request body
+	MethodsWithoutBody = []string{
+		"GET",
+		"HEAD",
+	}
+
+	// AllowedAuthTypes means a list of authentication types allowed
+	AllowedAuthTypes = []string{
+		BasicAuth,
+		BearerAuth,
+		OAuth2Auth,
 	}
 )
 
@@ -16,9 +16,8 @@ limitations under the License.
 package controller
 
 import (
-	"strconv"
-
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
 )
@@ -39,61 +38,12 @@ func ParseTemplates(items []map[string]*dynamodb.AttributeValue) ([]schema.Templ
 }
 
 // ChangeItemToConfig changes item value from dynamoDB to schema.Template
+// Item is decoded with the same tags used to save it so that every field of template is kept.
 func ChangeItemToConfig(item map[string]*dynamodb.AttributeValue) (*schema.Template, error) {
-	config := &schema.Template{
-		Name: item["name"].S,
-	}
-
-	interval, err := strconv.Atoi(*item["interval"].N)
-	if err != nil {
+	config := &schema.Template{}
+	if err := dynamodbattribute.UnmarshalMap(item, config); err != nil {
 		return nil, err
 	}
-	config.Interval = &interval
-
-	timeout, err := strconv.Atoi(*item["timeout"].N)
-	if err != nil {
-		return nil, err
-	}
-	config.Timeout = &timeout
-
-	regions := []schema.Region{}
-	for _, region := range item["regions"].L {
-		regions = append(regions, schema.Region{Region: region.M["region"].S})
-	}
-	config.Regions = regions
-
-	slackURLs := []string{}
-	for _, url := range item["slack_urls"].SS {
-		slackURLs = append(slackURLs, *url)
-	}
-	config.SlackURLs = slackURLs
-
-	targets := []schema.Target{}
-	for _, target := range item["targets"].L {
-		t := schema.Target{
-			URL:    target.M["url"].S,
-			Method: target.M["method"].S,
-		}
-
-		if _, ok := target.M["header"]; ok {
-			headers := map[string]string{}
-			for key, val := range target.M["header"].M {
-				headers[key] = *val.S
-			}
-			t.Header = headers
-		}
-
-		if _, ok := target.M["body"]; ok {
-			bodies := map[string]string{}
-			for key, val := range target.M["body"].M {
-				bodies[key] = *val.S
-			}
-			t.Body = bodies
-		}
-
-		targets = append(targets, t)
-	}
-	config.Targets = targets
 
 	return config, nil
 }
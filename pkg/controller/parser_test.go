@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestChangeItemToConfig(t *testing.T) {
+	config := schema.Template{
+		Name:      aws.String("sample"),
+		Interval:  aws.Int(60),
+		Timeout:   aws.Int(10),
+		SlackURLs: []string{"https://hooks.slack.com/services/sample"},
+		Regions:   []schema.Region{{Region: aws.String("ap-northeast-2")}},
+		Targets: []schema.Target{
+			{
+				Type:   aws.String("http"),
+				URL:    aws.String("api.example.com"),
+				Port:   aws.String("443"),
+				Method: aws.String("PUT"),
+				Query:  map[string]string{"page": "1"},
+				Header: map[string]string{"Authorization": "Bearer token"},
+				Payload: &schema.Payload{
+					JSON: map[string]interface{}{
+						"order": map[string]interface{}{"id": float64(42), "items": []interface{}{"a", "b"}},
+					},
+				},
+				Timeout: aws.Int(3),
+				Checks:  &schema.Checks{Status: []string{"2xx"}},
+			},
+			{
+				URL:    aws.String("api.example.com"),
+				Port:   aws.String("443"),
+				Method: aws.String("POST"),
+				Payload: &schema.Payload{
+					Multipart: []schema.MultipartField{
+						{Name: aws.String("name"), Value: aws.String("sample")},
+						{Name: aws.String("file"), File: aws.String("/tmp/sample.txt")},
+					},
+				},
+			},
+			{
+				URL: aws.String("api.example.com"),
+				Steps: []schema.Step{
+					{
+						Name:    aws.String("login"),
+						Method:  aws.String("POST"),
+						Payload: &schema.Payload{Form: map[string]string{"user": "sample"}},
+						Extract: []schema.Extraction{{Name: aws.String("token"), Header: aws.String("X-Token")}},
+					},
+				},
+			},
+			{
+				Type: aws.String("ping"),
+				URL:  aws.String("api.example.com"),
+				Ping: &schema.PingOption{Count: aws.Int(5)},
+			},
+		},
+	}
+
+	item, err := dynamodbattribute.MarshalMap(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ChangeItemToConfig(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(config, *output) {
+		t.Errorf("expected: %+v, got: %+v", config, *output)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
+	"github.com/DevopsArtFactory/bigshot/pkg/shot"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+)
+
+const (
+	// TextFormat is plain text report
+	TextFormat = "text"
+
+	// JSONFormat is JSON report
+	JSONFormat = "json"
+
+	// HTMLFormat is HTML report
+	HTMLFormat = "html"
+)
+
+// Export writes reports with format
+func Export(w io.Writer, reports []*Report, format string) error {
+	switch format {
+	case TextFormat, "":
+		return ExportText(w, reports)
+	case JSONFormat:
+		return ExportJSON(w, reports)
+	case HTMLFormat:
+		return ExportHTML(w, reports)
+	}
+
+	return fmt.Errorf("report format is not supported: %s", format)
+}
+
+// ExportText writes reports as text tables
+func ExportText(w io.Writer, reports []*Report) error {
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s: %s %s\n", color.DecorateAttr("bold", "Target"), report.Method, report.Target)
+		fmt.Fprintf(w, "%s: %d/s for %s\n", color.DecorateAttr("bold", "Rate"), report.Rate, report.Duration)
+
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Region", "Requests", "Rate", "Throughput", "Success", "P50", "P90", "P95", "P99", "Max"})
+		for _, summary := range append(report.Regions, report.Global) {
+			if len(summary.Error) > 0 {
+				table.Append([]string{summary.Region, "-", "-", "-", "-", "-", "-", "-", "-", "-"})
+				continue
+			}
+			table.Append([]string{
+				summary.Region,
+				fmt.Sprintf("%d", summary.Requests),
+				fmt.Sprintf("%.2f/s", summary.Rate),
+				fmt.Sprintf("%.2f/s", summary.Throughput),
+				fmt.Sprintf("%.2f%%", summary.Success*100),
+				summary.Latencies.P50.String(),
+				summary.Latencies.P90.String(),
+				summary.Latencies.P95.String(),
+				summary.Latencies.P99.String(),
+				summary.Latencies.Max.String(),
+			})
+		}
+		table.SetAlignment(tablewriter.ALIGN_CENTER)
+		table.SetRowLine(true)
+		table.Render()
+
+		breakdown := tablewriter.NewWriter(w)
+		breakdown.SetHeader([]string{"Region", "Status Codes", "Errors"})
+		breakdown.SetAutoWrapText(false)
+		for _, summary := range append(report.Regions, report.Global) {
+			if len(summary.Error) > 0 {
+				breakdown.Append([]string{summary.Region, "-", summary.Error})
+				continue
+			}
+			breakdown.Append([]string{
+				summary.Region,
+				shot.FormatStatusCodes(summary.StatusCodes),
+				strings.ReplaceAll(shot.FormatErrors(summary.Errors), ", ", "\n"),
+			})
+		}
+		breakdown.SetRowLine(true)
+		breakdown.Render()
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// ExportJSON writes reports as JSON
+func ExportJSON(w io.Writer, reports []*Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(reports)
+}
+
+// ExportHTML writes reports as HTML document
+func ExportHTML(w io.Writer, reports []*Report) error {
+	funcMap := template.FuncMap{
+		"statusCodes": shot.FormatStatusCodes,
+		"percent": func(f float64) string {
+			return fmt.Sprintf("%.2f%%", f*100)
+		},
+	}
+
+	t := template.Must(template.New("Load Test Report").Funcs(funcMap).Parse(templates.LoadReportHTMLTemplate))
+
+	return t.Execute(w, struct {
+		Reports []*Report
+	}{
+		Reports: reports,
+	})
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/sirupsen/logrus"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/builder"
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/shot"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// GlobalRegion is the name of merged summary
+const GlobalRegion = "global"
+
+type Report struct {
+	Template   string        `json:"template"`
+	Target     string        `json:"target"`
+	Method     string        `json:"method"`
+	Rate       int           `json:"rate"`
+	Duration   time.Duration `json:"duration"`
+	MinSuccess float64       `json:"min_success"`
+	Global     Summary       `json:"global"`
+	Regions    []Summary     `json:"regions"`
+}
+
+type Summary struct {
+	Region      string           `json:"region"`
+	Requests    uint64           `json:"requests"`
+	Rate        float64          `json:"rate"`
+	Throughput  float64          `json:"throughput"`
+	Success     float64          `json:"success"`
+	Latencies   schema.Latencies `json:"latencies"`
+	StatusCodes map[string]int   `json:"status_codes"`
+	Errors      map[string]int   `json:"errors"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// RegionResult is the result of load test slice from a region
+type RegionResult struct {
+	Region  string
+	Metrics *schema.LoadMetrics
+	Err     error
+}
+
+// Failed returns whether any region failed to run load test or success ratio is too low
+func (r *Report) Failed() bool {
+	if r.Global.Requests == 0 || r.Global.Success < r.MinSuccess {
+		return true
+	}
+
+	for _, region := range r.Regions {
+		if len(region.Error) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitRate splits the total rate into n slices
+func SplitRate(rate, n int) []int {
+	if n <= 0 {
+		return nil
+	}
+
+	slices := make([]int, n)
+	for i := range slices {
+		slices[i] = rate / n
+		if i < rate%n {
+			slices[i]++
+		}
+	}
+
+	return slices
+}
+
+// GetTargetRegions returns regions to run load test of target
+func GetTargetRegions(template *schema.Template, target schema.Target) []string {
+	var regions []string
+	for _, region := range template.Regions {
+		if target.Internal != nil && *target.Internal && !tools.IsStringInArray(*region.Region, target.Regions) {
+			continue
+		}
+		regions = append(regions, *region.Region)
+	}
+
+	return regions
+}
+
+// Run runs distributed load test of target with regional workers
+func Run(template *schema.Template, target schema.Target) (*Report, error) {
+	if target.URL == nil || target.Method == nil {
+		return nil, errors.New("url and method of target are required")
+	}
+
+	regions := GetTargetRegions(template, target)
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("no region is available to run load test: %s", *target.URL)
+	}
+
+	option := schema.LoadOption{}
+	if target.Load != nil {
+		option = *target.Load
+	}
+
+	rate := constants.DefaultLoadRate
+	if option.Rate != nil && *option.Rate > 0 {
+		rate = *option.Rate
+	}
+
+	slices := SplitRate(rate, len(regions))
+
+	var wg sync.WaitGroup
+	ch := make(chan RegionResult, len(regions))
+	for i, region := range regions {
+		if slices[i] == 0 {
+			continue
+		}
+
+		slice := option
+		slice.Rate = aws.Int(slices[i])
+
+		wg.Add(1)
+		go func(region string, slice schema.LoadOption) {
+			defer wg.Done()
+			metrics, err := runSlice(template, target, region, slice)
+			ch <- RegionResult{Region: region, Metrics: metrics, Err: err}
+		}(region, slice)
+	}
+
+	wg.Wait()
+	close(ch)
+
+	var results []RegionResult
+	for result := range ch {
+		results = append(results, result)
+	}
+
+	_, targetURL := shot.GetTargetURL(*target.URL, aws.StringValue(target.Port))
+	report, err := NewReport(results)
+	if err != nil {
+		return nil, err
+	}
+	report.Template = aws.StringValue(template.Name)
+	report.Target = targetURL
+	report.Method = *target.Method
+	report.Rate = rate
+	report.MinSuccess = constants.DefaultMinSuccess
+	if option.MinSuccess != nil {
+		report.MinSuccess = *option.MinSuccess
+	}
+
+	return report, nil
+}
+
+// runSlice invokes a regional worker with a slice of load test
+func runSlice(template *schema.Template, target schema.Target, region string, slice schema.LoadOption) (*schema.LoadMetrics, error) {
+	timeout := builder.RequestTimeout(&target, template.Timeout)
+
+	data := map[string]interface{}{
+		"type":          constants.LoadType,
+		"target":        *target.URL,
+		"port":          aws.StringValue(target.Port),
+		"method":        *target.Method,
+		"timeout":       timeout,
+		"load":          slice,
+		"result_needed": true,
+	}
+
+	if target.Body != nil {
+		data["body"] = target.Body
+	}
+
+	if target.Header != nil {
+		data["header"] = target.Header
+	}
+
+	if target.Payload != nil {
+		data["payload"] = target.Payload
+	}
+
+	if target.Query != nil {
+		data["query"] = target.Query
+	}
+
+	if target.Auth != nil {
+		data["auth"] = target.Auth
+	}
+
+	if target.TLS != nil {
+		data["tls"] = target.TLS
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Load test slice will be run in %s: %d requests per second", region, *slice.Rate)
+
+	internal := target.Internal != nil && *target.Internal
+	out, err := client.NewLambdaClient(region).Trigger(aws.String(region), template.Name, payload, internal)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result *schema.Result `json:"result"`
+	}
+	if err := json.Unmarshal(out, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil || response.Result.Load == nil {
+		return nil, fmt.Errorf("worker in %s returns no load test result", region)
+	}
+
+	return response.Result.Load, nil
+}
+
+// NewReport merges regional results into a report
+func NewReport(results []RegionResult) (*Report, error) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Region < results[j].Region
+	})
+
+	report := Report{}
+	global := Summary{
+		Region:      GlobalRegion,
+		StatusCodes: map[string]int{},
+		Errors:      map[string]int{},
+	}
+	merged := shot.NewLatencyHistogram()
+	var success float64
+
+	for _, result := range results {
+		if result.Err != nil {
+			report.Regions = append(report.Regions, Summary{
+				Region: result.Region,
+				Error:  result.Err.Error(),
+			})
+			continue
+		}
+
+		metrics := result.Metrics
+		histogram, err := hdrhistogram.Decode([]byte(metrics.Histogram))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode latency histogram from %s: %s", result.Region, err.Error())
+		}
+
+		if dropped := merged.Merge(histogram); dropped > 0 {
+			logrus.Warnf("%d latency values from %s are dropped while merging", dropped, result.Region)
+		}
+
+		report.Regions = append(report.Regions, Summary{
+			Region:      result.Region,
+			Requests:    metrics.Requests,
+			Rate:        metrics.Rate,
+			Throughput:  metrics.Throughput,
+			Success:     metrics.Success,
+			Latencies:   GetLatencies(histogram),
+			StatusCodes: metrics.StatusCodes,
+			Errors:      metrics.Errors,
+		})
+
+		global.Requests += metrics.Requests
+		global.Rate += metrics.Rate
+		global.Throughput += metrics.Throughput
+		success += metrics.Success * float64(metrics.Requests)
+		for code, count := range metrics.StatusCodes {
+			global.StatusCodes[code] += count
+		}
+		for e, count := range metrics.Errors {
+			global.Errors[e] += count
+		}
+
+		if metrics.Duration > report.Duration {
+			report.Duration = metrics.Duration
+		}
+	}
+
+	if global.Requests > 0 {
+		global.Success = success / float64(global.Requests)
+	}
+	global.Latencies = GetLatencies(merged)
+	report.Global = global
+
+	return &report, nil
+}
+
+// GetLatencies returns latency percentiles from histogram in microseconds
+func GetLatencies(histogram *hdrhistogram.Histogram) schema.Latencies {
+	if histogram.TotalCount() == 0 {
+		return schema.Latencies{}
+	}
+
+	return schema.Latencies{
+		Mean: microseconds(histogram.Mean()),
+		P50:  microseconds(float64(histogram.ValueAtQuantile(50))),
+		P90:  microseconds(float64(histogram.ValueAtQuantile(90))),
+		P95:  microseconds(float64(histogram.ValueAtQuantile(95))),
+		P99:  microseconds(float64(histogram.ValueAtQuantile(99))),
+		Max:  microseconds(float64(histogram.Max())),
+	}
+}
+
+// microseconds changes microseconds to duration
+func microseconds(v float64) time.Duration {
+	return time.Duration(math.Round(v)) * time.Microsecond
+}
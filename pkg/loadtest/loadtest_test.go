@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/shot"
+)
+
+func TestSplitRate(t *testing.T) {
+	testData := []struct {
+		Rate   int
+		N      int
+		Output []int
+	}{
+		{
+			Rate:   10,
+			N:      2,
+			Output: []int{5, 5},
+		},
+		{
+			Rate:   10,
+			N:      3,
+			Output: []int{4, 3, 3},
+		},
+		{
+			Rate:   1,
+			N:      3,
+			Output: []int{1, 0, 0},
+		},
+		{
+			Rate:   10,
+			N:      0,
+			Output: nil,
+		},
+	}
+
+	for _, td := range testData {
+		output := SplitRate(td.Rate, td.N)
+		if fmt.Sprint(output) != fmt.Sprint(td.Output) {
+			t.Errorf("expected: %v / output: %v", td.Output, output)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	results := []RegionResult{
+		{
+			Region:  "us-east-1",
+			Metrics: loadMetrics(t, 100, 1, map[string]int{"200": 100}, 10*time.Millisecond),
+		},
+		{
+			Region:  "ap-northeast-2",
+			Metrics: loadMetrics(t, 100, 0.5, map[string]int{"200": 50, "500": 50}, 30*time.Millisecond),
+		},
+		{
+			Region: "eu-west-1",
+			Err:    errors.New("worker timeout"),
+		},
+	}
+
+	report, err := NewReport(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Regions) != 3 || report.Regions[0].Region != "ap-northeast-2" {
+		t.Errorf("regions are not sorted: %v", report.Regions)
+	}
+
+	if report.Regions[1].Error != "worker timeout" {
+		t.Errorf("expected error of eu-west-1 / output: %s", report.Regions[1].Error)
+	}
+
+	global := report.Global
+	if global.Requests != 200 {
+		t.Errorf("expected: 200 requests / output: %d", global.Requests)
+	}
+
+	if global.Success != 0.75 {
+		t.Errorf("expected: 0.75 success / output: %f", global.Success)
+	}
+
+	if global.StatusCodes["200"] != 150 || global.StatusCodes["500"] != 50 {
+		t.Errorf("status codes are not merged: %v", global.StatusCodes)
+	}
+
+	if p50 := global.Latencies.P50.Round(time.Millisecond); p50 != 10*time.Millisecond {
+		t.Errorf("expected: 10ms p50 / output: %s", p50)
+	}
+
+	if max := global.Latencies.Max.Round(time.Millisecond); max != 30*time.Millisecond {
+		t.Errorf("expected: 30ms max / output: %s", max)
+	}
+
+	if !report.Failed() {
+		t.Error("report with failed region should be failed")
+	}
+}
+
+// loadMetrics returns load metrics with the same latency of every request
+func loadMetrics(t *testing.T, requests uint64, success float64, codes map[string]int, latency time.Duration) *schema.LoadMetrics {
+	histogram := shot.NewLatencyHistogram()
+	for i := uint64(0); i < requests; i++ {
+		shot.RecordLatency(histogram, latency)
+	}
+
+	encoded, err := histogram.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &schema.LoadMetrics{
+		Requests:    requests,
+		Success:     success,
+		StatusCodes: codes,
+		Histogram:   string(encoded),
+	}
+}
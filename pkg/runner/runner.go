@@ -23,11 +23,14 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/sirupsen/logrus"
 
 	"github.com/DevopsArtFactory/bigshot/pkg/builder"
@@ -35,8 +38,10 @@ import (
 	"github.com/DevopsArtFactory/bigshot/pkg/color"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
 	"github.com/DevopsArtFactory/bigshot/pkg/generator"
+	"github.com/DevopsArtFactory/bigshot/pkg/loadtest"
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
 	"github.com/DevopsArtFactory/bigshot/pkg/server"
+	"github.com/DevopsArtFactory/bigshot/pkg/shot"
 	"github.com/DevopsArtFactory/bigshot/pkg/templates"
 	"github.com/DevopsArtFactory/bigshot/pkg/tools"
 )
@@ -214,6 +219,15 @@ func (r *Runner) UpdateTemplate(args []string) error {
 
 // Run triggers a single worker manager
 func (r *Runner) Run(args []string) error {
+	if r.Builder.Flags.Local || r.Builder.Config != nil {
+		template, err := r.GetLocalTemplate(args)
+		if err != nil {
+			return err
+		}
+
+		return r.RunLocal(template)
+	}
+
 	name, err := r.GetTargetFunctionName(args)
 	if err != nil {
 		return err
@@ -223,6 +237,149 @@ func (r *Runner) Run(args []string) error {
 	return nil
 }
 
+// GetLocalTemplate returns template from configuration file or metadata table
+func (r *Runner) GetLocalTemplate(args []string) (*schema.Template, error) {
+	if r.Builder.Config != nil {
+		return r.Builder.Config, nil
+	}
+
+	name, err := r.GetTargetFunctionName(args)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamoDB := client.NewDynamoDBClient(r.Builder.DefaultRegion)
+	item, err := dynamoDB.GetTemplate(name, tools.GenerateNewTableName())
+	if err != nil {
+		return nil, err
+	}
+
+	var template schema.Template
+	if err := dynamodbattribute.UnmarshalMap(item, &template); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// RunLocal runs every target check of template from this machine
+func (r *Runner) RunLocal(template *schema.Template) error {
+	logrus.Infof("Running bigshot checks locally: %s", *template.Name)
+
+	var failed []string
+	for _, target := range template.Targets {
+		label := targetLabel(target)
+
+		// target which cannot be set up fails alone like in worker
+		shooter, err := shot.NewShooterWithTarget(target, constants.LocalRegion)
+		if err != nil {
+			color.Red.Fprintf(os.Stdout, "[FAIL] %s: %s", label, err.Error())
+			failed = append(failed, label)
+			continue
+		}
+
+		result, err := shooter.RunWithResult()
+		if err != nil {
+			color.Red.Fprintf(os.Stdout, "[FAIL] %s: %s", label, err.Error())
+			failed = append(failed, label)
+			continue
+		}
+
+		if result.Failed() {
+			color.Red.Fprintf(os.Stdout, "[FAIL] %s", label)
+			failed = append(failed, label)
+		} else {
+			color.Green.Fprintf(os.Stdout, "[PASS] %s", label)
+		}
+
+		if err := shooter.PrintResult(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d checks failed: %s", len(failed), len(template.Targets), strings.Join(failed, ", "))
+	}
+
+	logrus.Infof("All %d checks passed", len(template.Targets))
+
+	return nil
+}
+
+// Load runs distributed load test of template targets with regional workers
+func (r *Runner) Load(args []string) error {
+	template, err := r.GetLocalTemplate(args)
+	if err != nil {
+		return err
+	}
+
+	var targets []schema.Target
+	for _, target := range template.Targets {
+		if target.Type != nil && *target.Type == constants.LoadType {
+			targets = append(targets, target)
+		}
+	}
+
+	if len(targets) == 0 {
+		return fmt.Errorf("there is no load test target in template: %s", aws.StringValue(template.Name))
+	}
+
+	logrus.Infof("Running distributed load test: %s", aws.StringValue(template.Name))
+
+	var reports []*loadtest.Report
+	var failed []string
+	for _, target := range targets {
+		report, err := loadtest.Run(template, target)
+		if err != nil {
+			return err
+		}
+
+		if report.Failed() {
+			failed = append(failed, targetLabel(target))
+		}
+		reports = append(reports, report)
+	}
+
+	out := os.Stdout
+	if len(r.Builder.Flags.OutputFile) > 0 {
+		f, err := os.Create(r.Builder.Flags.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := loadtest.Export(out, reports, r.Builder.Flags.Output); err != nil {
+		return err
+	}
+
+	if len(r.Builder.Flags.OutputFile) > 0 {
+		logrus.Infof("Load test report is written: %s", r.Builder.Flags.OutputFile)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d load tests failed: %s", len(failed), len(targets), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// targetLabel returns short description of target
+func targetLabel(target schema.Target) string {
+	label := aws.StringValue(target.URL)
+	if target.Port != nil {
+		label = fmt.Sprintf("%s:%s", label, *target.Port)
+	}
+
+	if target.Method != nil {
+		label = fmt.Sprintf("%s %s", *target.Method, label)
+	}
+
+	return label
+}
+
 // CreateTrigger creates a cloudwatch rule to start bigshot
 func (r *Runner) CreateTrigger(config *schema.Template) error {
 	region, err := builder.GetDefaultRegion(constants.DefaultProfile)
@@ -461,7 +618,7 @@ func mapConfig(item map[string]*dynamodb.AttributeValue) (*schema.Template, erro
 		case "targets":
 			var targets []schema.Target
 			for _, target := range v.L {
-				targets = append(targets, targetConfig(*target.M["method"].S, *target.M["url"].S))
+				targets = append(targets, targetConfig(target.M["type"], target.M["method"], target.M["url"]))
 			}
 			conf.Targets = targets
 		case "timeout":
@@ -485,11 +642,19 @@ func regionConfig(region string) schema.Region {
 }
 
 // targetConfig creates target config struct
-func targetConfig(method, url string) schema.Target {
-	return schema.Target{
-		Method: &method,
-		URL:    &url,
+func targetConfig(t, method, url *dynamodb.AttributeValue) schema.Target {
+	target := schema.Target{}
+	if t != nil {
+		target.Type = t.S
+	}
+	if method != nil {
+		target.Method = method.S
 	}
+	if url != nil {
+		target.URL = url.S
+	}
+
+	return target
 }
 
 // SetGenerator setup a new Generator
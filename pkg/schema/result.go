@@ -15,17 +15,223 @@ limitations under the License.
 */
 package schema
 
-import "time"
+import (
+	"net/http"
+	"time"
+)
 
 type Result struct {
 	TracingData TracingData
 	Response    Response
+	Ping        *PingStatistics   `json:",omitempty"`
+	Load        *LoadMetrics      `json:",omitempty"`
+	Assertions  []Assertion       `json:",omitempty"`
+	Failure     *Failure          `json:",omitempty"`
+	Certificate *Certificate      `json:",omitempty"`
+	DNS         *DNSResult        `json:",omitempty"`
+	TCP         *TCPResult        `json:",omitempty"`
+	GRPC        *GRPCResult       `json:",omitempty"`
+	WebSocket   *WebSocketResult  `json:",omitempty"`
+	Steps       []StepResult      `json:",omitempty"`
+	Redirects   []RedirectHop     `json:",omitempty"`
+	Backends    []BackendResult   `json:",omitempty"`
+	Attempts    []Attempt         `json:",omitempty"`
+	Samples     *SampleStatistics `json:",omitempty"`
+
+	// Warm is result of the second request on the connection of the first one
+	Warm *Result `json:",omitempty"`
+}
+
+// Failed returns whether the check is regarded as failure
+// Failure of warm request also fails the check.
+func (r Result) Failed() bool {
+	if r.Failure != nil || (r.Warm != nil && r.Warm.Failed()) {
+		return true
+	}
+
+	if r.Ping != nil {
+		return r.Ping.PacketsRecv == 0 || r.Ping.PacketLoss > r.Ping.MaxPacketLoss
+	}
+
+	if r.Load != nil {
+		return r.Load.Requests == 0 || r.Load.Success < r.Load.MinSuccess
+	}
+
+	if len(r.Assertions) > 0 || r.DNS != nil || r.TCP != nil || r.GRPC != nil || r.WebSocket != nil {
+		return len(r.FailedAssertions()) > 0
+	}
+
+	return r.Response.StatusCode != http.StatusOK
+}
+
+// FailedAssertions returns assertions which are not passed
+func (r Result) FailedAssertions() []Assertion {
+	var failed []Assertion
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			failed = append(failed, assertion)
+		}
+	}
+
+	return failed
+}
+
+type Failure struct {
+	// Phase of request where failure happened like dns, tcp, tls, first_byte, content or http
+	Phase string
+
+	// Reason of failure like dns_nxdomain, tcp_refused, tls_expired_cert or http_status
+	Reason string
+
+	// Message is the original error message
+	Message string
+}
+
+type StepResult struct {
+	Name   string
+	Method string
+	URL    string
+	Result Result
+}
+
+// SampleStatistics is statistics of timings of samples which got response
+type SampleStatistics struct {
+	Count            int
+	Failed           int
+	DNSLookup        PhaseStatistics
+	TCPConnection    PhaseStatistics
+	TLSHandShacking  PhaseStatistics
+	ServerProcessing PhaseStatistics
+	ContentTransfer  PhaseStatistics
+	Total            PhaseStatistics
+}
+
+// PhaseStatistics is min, median, 95th percentile and max of timings of a phase
+type PhaseStatistics struct {
+	Min    time.Duration
+	Median time.Duration
+	P95    time.Duration
+	Max    time.Duration
+}
+
+// Attempt is outcome of one try of check with retries
+type Attempt struct {
+	Number     int
+	Passed     bool
+	StatusCode int
+	Failure    *Failure `json:",omitempty"`
+	Total      time.Duration
+
+	// Backoff is the time waited before the attempt
+	Backoff time.Duration `json:",omitempty"`
+}
+
+// SucceededOnRetry returns whether the check passed after failed attempts
+func (r Result) SucceededOnRetry() bool {
+	return len(r.Attempts) > 1 && r.Attempts[len(r.Attempts)-1].Passed
+}
+
+// BackendResult is result of one IP of target
+type BackendResult struct {
+	IP     string
+	Result Result
+}
+
+// RedirectHop is one request of redirect chain
+// The last hop is the final response.
+type RedirectHop struct {
+	URL         string
+	StatusCode  int
+	Location    string `json:",omitempty"`
+	TracingData TracingData
+}
+
+type TCPResult struct {
+	Address       string
+	BytesSent     int
+	BytesReceived int
+
+	// Response is the beginning of received bytes
+	Response string
+}
+
+type GRPCResult struct {
+	Address     string
+	Service     string
+	TLS         bool
+	Status      string
+	ConnectTime time.Duration
+	RPCLatency  time.Duration
+
+	// WatchStatus is the first status from Health/Watch stream
+	WatchStatus  string        `json:",omitempty"`
+	WatchLatency time.Duration `json:",omitempty"`
+}
+
+type WebSocketResult struct {
+	URL            string
+	Subprotocol    string
+	UpgradeLatency time.Duration
+
+	// RoundTrip is the duration from sending message to receiving the matched reply
+	RoundTrip time.Duration `json:",omitempty"`
+	Reply     string        `json:",omitempty"`
+}
+
+type DNSResult struct {
+	Name           string
+	RecordType     string
+	Nameserver     string
+	Rcode          string
+	ResolutionTime time.Duration
+	Answers        []DNSAnswer
+}
+
+type DNSAnswer struct {
+	Type  string
+	Value string
+	TTL   uint32
+}
+
+type Certificate struct {
+	Subject         string
+	SANs            []string
+	Issuer          string
+	SerialNumber    string
+	NotAfter        time.Time
+	DaysUntilExpiry int
+	ChainVerified   bool
+	ChainError      string `json:",omitempty"`
+
+	// Threshold is the expiry threshold in days which is crossed today
+	Threshold int `json:",omitempty"`
+}
+
+type Assertion struct {
+	Name     string
+	Expected string
+	Actual   string
+	Passed   bool
 }
 
 type Response struct {
 	StatusCode int
 	StatusMsg  string
 	Header     map[string][]string
+	Protocol   *Protocol `json:",omitempty"`
+
+	// Body is only kept in memory for checks
+	Body []byte `json:"-"`
+}
+
+// Protocol is the protocol negotiated for the response
+type Protocol struct {
+	HTTPVersion    string
+	ALPN           string `json:",omitempty"`
+	TLSVersion     string `json:",omitempty"`
+	CipherSuite    string `json:",omitempty"`
+	SessionResumed bool
+	OCSPStapled    bool
 }
 
 type TracingData struct {
@@ -49,4 +255,48 @@ type TracingData struct {
 	ServerProcessing time.Duration
 	ContentTransfer  time.Duration
 	Total            time.Duration
+
+	// Reused means connection was reused from pool and WasIdle means it was idle before reused
+	Reused  bool
+	WasIdle bool
+}
+
+type PingStatistics struct {
+	Addr          string
+	IPAddr        string
+	Privileged    bool
+	PacketsSent   int
+	PacketsRecv   int
+	PacketLoss    float64
+	MaxPacketLoss float64
+	MinRtt        time.Duration
+	AvgRtt        time.Duration
+	MaxRtt        time.Duration
+	StdDevRtt     time.Duration
+}
+
+type LoadMetrics struct {
+	URL         string
+	Method      string
+	Rate        float64
+	Duration    time.Duration
+	Requests    uint64
+	Throughput  float64
+	Success     float64
+	MinSuccess  float64
+	Latencies   Latencies
+	StatusCodes map[string]int
+	Errors      map[string]int
+
+	// Histogram is base64 encoded HDR histogram of latencies in microseconds
+	Histogram string `json:",omitempty"`
+}
+
+type Latencies struct {
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
 }
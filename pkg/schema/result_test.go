@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+)
+
+func TestResultFailedPacketLoss(t *testing.T) {
+	testData := []struct {
+		Name   string
+		Ping   PingStatistics
+		Failed bool
+	}{
+		{Name: "no loss", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 5, PacketLoss: 0}, Failed: false},
+		{Name: "loss without threshold", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 4, PacketLoss: 20}, Failed: true},
+		{Name: "loss under threshold", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 4, PacketLoss: 20, MaxPacketLoss: 40}, Failed: false},
+		{Name: "loss at threshold", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 3, PacketLoss: 40, MaxPacketLoss: 40}, Failed: false},
+		{Name: "loss over threshold", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 2, PacketLoss: 60, MaxPacketLoss: 40}, Failed: true},
+		{Name: "nothing received", Ping: PingStatistics{PacketsSent: 5, PacketsRecv: 0, PacketLoss: 100, MaxPacketLoss: 100}, Failed: true},
+	}
+
+	for _, td := range testData {
+		ping := td.Ping
+		if failed := (Result{Ping: &ping}).Failed(); failed != td.Failed {
+			t.Errorf("%s - expected failed: %t, got: %t", td.Name, td.Failed, failed)
+		}
+	}
+}
@@ -42,29 +42,441 @@ type Template struct {
 
 // Target configuration
 type Target struct {
-	// Target URL of API
+	// Type of check. Defaults to `http`.
+	// Valid types are
+	//  `http` (default): HTTP request tracing
+	//  `ping`: ICMP echo requests to the host of URL
+	//  `load`: load test with constant request rate
+	//  `dns`: DNS resolution of the host of URL
+	//  `tcp`: raw TCP connection to the host and port
+	//  `grpc`: gRPC health checking of the host and port
+	//  `websocket`: WebSocket upgrade and message round-trip
+	Type *string `yaml:"type,omitempty" json:"type"`
+
+	// Target URL of API. A full URL like `https://api.example.com:8443/health?verbose=1` is allowed.
+	// URL without scheme uses https only if port is `443`.
 	URL *string `yaml:"url,omitempty" json:"url"`
 
-	// Target Port of API
+	// Target Port of API. It is required only if URL has no scheme.
 	Port *string `yaml:"port,omitempty" json:"port"`
 
-	// API method
+	// API method. One of `GET`, `POST`, `PUT`, `PATCH`, `DELETE`, `HEAD` and `OPTIONS`.
 	Method *string `yaml:"method,omitempty" json:"method"`
 
-	// Body value of API
+	// Body value of API which is sent as JSON object
 	Body map[string]string `yaml:"body,omitempty" json:"body"`
 
-	// Header value of API
+	// Payload of API for body other than flat JSON object. It cannot be used with body.
+	Payload *Payload `yaml:"payload,omitempty" json:"payload,omitempty"`
+
+	// Query parameters added to URL
+	Query map[string]string `yaml:"query,omitempty" json:"query,omitempty"`
+
+	// Header value of API.
+	// Values of header, body, query, payload, auth and tls can be a secret reference like
+	// `${ssm:/bigshot/api-key}`, `${secretsmanager:arn#key}` or `${env:API_KEY}`.
+	// References are kept in template and resolved by worker right before request.
 	Header map[string]string `yaml:"header,omitempty" json:"header"`
 
+	// Auth adds Authorization header to requests of `http`, `load` and `websocket` type
+	Auth *AuthOption `yaml:"auth,omitempty" json:"auth,omitempty"`
+
+	// SigV4 signs requests of `http` type with AWS Signature Version 4 like API Gateway IAM auth
+	SigV4 *SigV4Option `yaml:"sigv4,omitempty" json:"sigv4,omitempty"`
+
 	// Target Request timeout
 	Timeout *int `yaml:"timeout,omitempty" json:"timeout"`
 
+	// Retries of `http` target before it is regarded as failure. Every attempt is recorded in result.
+	Retries *int `yaml:"retries,omitempty" json:"retries,omitempty"`
+
+	// Seconds to wait before the first retry which is doubled for every next retry. Defaults to `1`.
+	RetryBackoff *int `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
+
+	// Samples option of `http` target which sends several requests in a check for latency statistics
+	Samples *SampleOption `yaml:"samples,omitempty" json:"samples,omitempty"`
+
+	// Warm sends the second request of `http` target on kept-alive connection of the first one
+	// so that both cold and warm timings are reported
+	Warm *bool `yaml:"warm,omitempty" json:"warm,omitempty"`
+
 	// Internal means whether or not to run within VPC
 	Internal *bool `yaml:"internal" json:"internal"`
 
 	// Regions means the list of regions to run bigshot check
 	Regions []string `yaml:"regions,omitempty" json:"regions"`
+
+	// Ping option for `ping` type
+	Ping *PingOption `yaml:"ping,omitempty" json:"ping,omitempty"`
+
+	// Load option for `load` type
+	Load *LoadOption `yaml:"load,omitempty" json:"load,omitempty"`
+
+	// DNS option for `dns` type
+	DNS *DNSOption `yaml:"dns,omitempty" json:"dns,omitempty"`
+
+	// TCP option for `tcp` type
+	TCP *TCPOption `yaml:"tcp,omitempty" json:"tcp,omitempty"`
+
+	// GRPC option for `grpc` type
+	GRPC *GRPCOption `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+
+	// WebSocket option for `websocket` type
+	WebSocket *WebSocketOption `yaml:"websocket,omitempty" json:"websocket,omitempty"`
+
+	// Checks are assertions on response of `http` type.
+	// Status code should be `200` if no check is specified.
+	Checks *Checks `yaml:"checks,omitempty" json:"checks,omitempty"`
+
+	// Steps of `http` type which are requested in order as one transaction.
+	// URL and port of target are used as base of each step and header is sent with every step.
+	Steps []Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+
+	// Certificate option of https target
+	Certificate *CertificateOption `yaml:"certificate,omitempty" json:"certificate,omitempty"`
+
+	// TLS option of `http`, `load`, `websocket` and `grpc` target like client certificate for mutual TLS
+	TLS *TLSOption `yaml:"tls,omitempty" json:"tls,omitempty"`
+
+	// Resolve pins host of `http` target to IPs like `curl --resolve` while SNI and Host header are kept.
+	// Every IP is probed separately with its own result if there are more than one.
+	Resolve *ResolveOption `yaml:"resolve,omitempty" json:"resolve,omitempty"`
+
+	// FollowRedirects follows redirects of `http` type and records every hop.
+	// Redirect response is regarded as the final response if it is not specified.
+	FollowRedirects *RedirectOption `yaml:"follow_redirects,omitempty" json:"follow_redirects,omitempty"`
+}
+
+// TLSOption configuration
+// PEM values can be inline or a secret reference like `${ssm:/bigshot/client-key}` or
+// `${secretsmanager:arn#key}` which is resolved by worker when it runs.
+type TLSOption struct {
+	// Client certificate in PEM for mutual TLS
+	ClientCert *string `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`
+
+	// Private key of client certificate in PEM
+	ClientKey *string `yaml:"client_key,omitempty" json:"client_key,omitempty"`
+
+	// CA bundle in PEM which is used instead of system roots to verify server certificate
+	CA *string `yaml:"ca,omitempty" json:"ca,omitempty"`
+
+	// Skip verification of server certificate. It should be used only for lab environments.
+	InsecureSkipVerify *bool `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"`
+}
+
+// AuthOption configuration
+type AuthOption struct {
+	// Type of authentication. One of `basic`, `bearer` and `oauth2`.
+	Type *string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// Username of `basic` type
+	Username *string `yaml:"username,omitempty" json:"username,omitempty"`
+
+	// Password of `basic` type
+	Password *string `yaml:"password,omitempty" json:"password,omitempty"`
+
+	// Token of `bearer` type
+	Token *string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Token endpoint of `oauth2` type. Token is requested with client credentials grant
+	// and cached by worker until it expires.
+	TokenURL *string `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+
+	// Client ID of `oauth2` type
+	ClientID *string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// Client secret of `oauth2` type
+	ClientSecret *string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// Scopes requested by `oauth2` type
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+
+	// Additional parameters of token request like `audience`
+	Params map[string]string `yaml:"params,omitempty" json:"params,omitempty"`
+}
+
+// SigV4Option configuration
+type SigV4Option struct {
+	// Signing name of service like `execute-api` or `lambda`.
+	// Defaults to service of API Gateway or Lambda function URL host.
+	Service *string `yaml:"service,omitempty" json:"service,omitempty"`
+
+	// Signing region. Defaults to region of the host or region of worker.
+	Region *string `yaml:"region,omitempty" json:"region,omitempty"`
+
+	// ARN of role to assume for signing. Role of worker is used by default.
+	RoleARN *string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+}
+
+// ResolveOption configuration
+// Only one of ips and all should be set.
+type ResolveOption struct {
+	// IPs to connect to instead of the address which DNS returns. Each IP is reported as a backend.
+	IPs []string `yaml:"ips,omitempty" json:"ips,omitempty"`
+
+	// All enables fan-out mode which resolves every A and AAAA record of host and probes each IP
+	// separately so that a broken backend behind load balancer is reported with its IP
+	All *bool `yaml:"all,omitempty" json:"all,omitempty"`
+}
+
+// RedirectOption configuration
+type RedirectOption struct {
+	// Maximum number of redirects to follow. Defaults to `10`.
+	MaxHops *int `yaml:"max_hops,omitempty" json:"max_hops,omitempty"`
+
+	// URL which the last hop should have like `https://www.example.com/`
+	FinalURL *string `yaml:"final_url,omitempty" json:"final_url,omitempty"`
+
+	// Whether http URL should be redirected to https.
+	// `false` means scheme should not be changed by redirects.
+	HTTPS *bool `yaml:"https,omitempty" json:"https,omitempty"`
+
+	// Whether apex domain should be redirected to `www` subdomain.
+	// `false` means final host should not have `www` prefix.
+	WWW *bool `yaml:"www,omitempty" json:"www,omitempty"`
+}
+
+// CertificateOption configuration
+type CertificateOption struct {
+	// Days before expiry to send alarm. Defaults to `[30, 14, 7]`.
+	ExpiryThresholds []int `yaml:"expiry_thresholds,omitempty" json:"expiry_thresholds,omitempty"`
+}
+
+// DNSOption configuration
+type DNSOption struct {
+	// Record type to query. One of `A`, `AAAA`, `CNAME`, `MX`, `TXT`, `NS` and `CAA`. Defaults to `A`.
+	RecordType *string `yaml:"record_type,omitempty" json:"record_type,omitempty"`
+
+	// Nameserver to query like `8.8.8.8` or `8.8.8.8:53`. System resolver is used by default.
+	Nameserver *string `yaml:"nameserver,omitempty" json:"nameserver,omitempty"`
+
+	// Whether the record should exist. Defaults to `true`.
+	Exists *bool `yaml:"exists,omitempty" json:"exists,omitempty"`
+
+	// Answers which should be in the response like `93.184.216.34` or `10 mail.example.com`
+	Expected []string `yaml:"expected,omitempty" json:"expected,omitempty"`
+
+	// Minimum TTL of answers in seconds
+	MinTTL *int `yaml:"min_ttl,omitempty" json:"min_ttl,omitempty"`
+
+	// Maximum TTL of answers in seconds
+	MaxTTL *int `yaml:"max_ttl,omitempty" json:"max_ttl,omitempty"`
+}
+
+// TCPOption configuration
+type TCPOption struct {
+	// Payload to send after connection is established
+	Payload *string `yaml:"payload,omitempty" json:"payload,omitempty"`
+
+	// Regular expression which response bytes should match.
+	// Response is read until it matches or timeout.
+	Expected *string `yaml:"expected,omitempty" json:"expected,omitempty"`
+}
+
+// GRPCOption configuration
+type GRPCOption struct {
+	// Service name to check. Empty name checks the health of whole server.
+	Service *string `yaml:"service,omitempty" json:"service,omitempty"`
+
+	// Whether to use TLS. Defaults to `true` if port is `443`.
+	TLS *bool `yaml:"tls,omitempty" json:"tls,omitempty"`
+
+	// Metadata sent with health check request
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+
+	// Watch waits for the first status from Health/Watch stream in addition to Health/Check
+	Watch *bool `yaml:"watch,omitempty" json:"watch,omitempty"`
+}
+
+// WebSocketOption configuration
+type WebSocketOption struct {
+	// Path of upgrade request like `/ws`. Defaults to `/`.
+	Path *string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Message to send after upgrade
+	Message *string `yaml:"message,omitempty" json:"message,omitempty"`
+
+	// Regular expression which a reply message should match.
+	// Messages are read until one matches or message timeout.
+	Expected *string `yaml:"expected,omitempty" json:"expected,omitempty"`
+
+	// Seconds to wait for the reply. Defaults to timeout of target.
+	MessageTimeout *int `yaml:"message_timeout,omitempty" json:"message_timeout,omitempty"`
+}
+
+// Checks configuration
+type Checks struct {
+	// Expected status codes. A code like `201`, a class like `2xx` or a range like `200-299` is allowed.
+	// Defaults to `200`.
+	Status []string `yaml:"status,omitempty" json:"status,omitempty"`
+
+	// Assertions on response headers
+	Headers []HeaderCheck `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Assertions on response body
+	Body []BodyCheck `yaml:"body,omitempty" json:"body,omitempty"`
+
+	// Assertions on values of JSON response body
+	JSONPath []JSONPathCheck `yaml:"json_path,omitempty" json:"json_path,omitempty"`
+
+	// Maximum total latency in milliseconds
+	MaxLatency *int `yaml:"max_latency,omitempty" json:"max_latency,omitempty"`
+}
+
+// Payload configuration
+// Only one of raw, json, form, multipart and file should be set.
+type Payload struct {
+	// Raw string sent as it is
+	Raw *string `yaml:"raw,omitempty" json:"raw,omitempty"`
+
+	// JSON value which can be nested
+	JSON interface{} `yaml:"json,omitempty" json:"json,omitempty"`
+
+	// Form values sent as `application/x-www-form-urlencoded`
+	Form map[string]string `yaml:"form,omitempty" json:"form,omitempty"`
+
+	// Multipart fields sent as `multipart/form-data`
+	Multipart []MultipartField `yaml:"multipart,omitempty" json:"multipart,omitempty"`
+
+	// Path of file sent as body. The file is read where the check runs.
+	File *string `yaml:"file,omitempty" json:"file,omitempty"`
+
+	// Content type of body. Defaults to the type of payload.
+	ContentType *string `yaml:"content_type,omitempty" json:"content_type,omitempty"`
+}
+
+// MultipartField configuration
+// Only one of value and file should be set.
+type MultipartField struct {
+	// Name of field
+	Name *string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Value of field
+	Value *string `yaml:"value,omitempty" json:"value,omitempty"`
+
+	// Path of file uploaded as field. The file is read where the check runs.
+	File *string `yaml:"file,omitempty" json:"file,omitempty"`
+}
+
+// Step configuration
+type Step struct {
+	// Name of step shown in result
+	Name *string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// API method. Defaults to `GET`.
+	Method *string `yaml:"method,omitempty" json:"method,omitempty"`
+
+	// Path appended to URL of target like `/orders/{{ order_id }}`.
+	// Variables extracted in earlier steps are replaced in path, header and body.
+	Path *string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Body value of API
+	Body map[string]string `yaml:"body,omitempty" json:"body,omitempty"`
+
+	// Payload of API for body other than flat JSON object
+	Payload *Payload `yaml:"payload,omitempty" json:"payload,omitempty"`
+
+	// Query parameters added to URL
+	Query map[string]string `yaml:"query,omitempty" json:"query,omitempty"`
+
+	// Header value of API which overrides header of target
+	Header map[string]string `yaml:"header,omitempty" json:"header,omitempty"`
+
+	// Checks are assertions on response of the step
+	Checks *Checks `yaml:"checks,omitempty" json:"checks,omitempty"`
+
+	// Extract values from response for later steps
+	Extract []Extraction `yaml:"extract,omitempty" json:"extract,omitempty"`
+}
+
+// Extraction configuration
+// Only one of json_path, regex and header should be set.
+type Extraction struct {
+	// Name of variable used like `{{ name }}`
+	Name *string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// JSONPath expression on response body like `$.token`
+	JSONPath *string `yaml:"json_path,omitempty" json:"json_path,omitempty"`
+
+	// Regular expression on response body. The first capture group is used if it exists.
+	Regex *string `yaml:"regex,omitempty" json:"regex,omitempty"`
+
+	// Name of response header
+	Header *string `yaml:"header,omitempty" json:"header,omitempty"`
+}
+
+// HeaderCheck configuration
+type HeaderCheck struct {
+	// Name of response header
+	Name *string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Expected value of header. Header only needs to exist if neither equals nor regex is set.
+	Equals *string `yaml:"equals,omitempty" json:"equals,omitempty"`
+
+	// Regular expression which header value should match
+	Regex *string `yaml:"regex,omitempty" json:"regex,omitempty"`
+}
+
+// BodyCheck configuration
+type BodyCheck struct {
+	// String which response body should contain
+	Contains *string `yaml:"contains,omitempty" json:"contains,omitempty"`
+
+	// Regular expression which response body should match
+	Regex *string `yaml:"regex,omitempty" json:"regex,omitempty"`
+}
+
+// JSONPathCheck configuration
+type JSONPathCheck struct {
+	// JSONPath expression like `$.status` or `$.items[0].id`
+	Path *string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Expected value. Values other than string are compared with their JSON representation.
+	Equals *string `yaml:"equals,omitempty" json:"equals,omitempty"`
+}
+
+// PingOption configuration
+type PingOption struct {
+	// Number of ICMP echo requests to send. Defaults to `3`.
+	Count *int `yaml:"count,omitempty" json:"count,omitempty"`
+
+	// Interval between echo requests in milliseconds. Defaults to `200`.
+	Interval *int `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// Privileged uses raw ICMP socket which requires CAP_NET_RAW.
+	// Unprivileged UDP ICMP socket is used by default.
+	Privileged *bool `yaml:"privileged,omitempty" json:"privileged,omitempty"`
+
+	// Maximum packet loss in percentage before the check fails. Defaults to `100`.
+	MaxPacketLoss *float64 `yaml:"max_packet_loss,omitempty" json:"max_packet_loss,omitempty"`
+}
+
+// SampleOption configuration
+type SampleOption struct {
+	// Number of requests in a check. Defaults to `1`.
+	Count *int `yaml:"count,omitempty" json:"count,omitempty"`
+
+	// Interval between requests in milliseconds. Defaults to `0`.
+	Interval *int `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// ReuseConnection sends requests on kept-alive connection.
+	// New connection is made for every request by default.
+	ReuseConnection *bool `yaml:"reuse_connection,omitempty" json:"reuse_connection,omitempty"`
+}
+
+// LoadOption configuration
+type LoadOption struct {
+	// Number of requests per second. Defaults to `10`.
+	Rate *int `yaml:"rate,omitempty" json:"rate,omitempty"`
+
+	// Duration of load test in seconds. Defaults to `10`.
+	Duration *int `yaml:"duration,omitempty" json:"duration,omitempty"`
+
+	// Maximum number of workers sending requests. Unlimited by default.
+	MaxWorkers *int `yaml:"max_workers,omitempty" json:"max_workers,omitempty"`
+
+	// Minimum ratio of successful requests before the check fails. Defaults to `1`.
+	MinSuccess *float64 `yaml:"min_success,omitempty" json:"min_success,omitempty"`
 }
 
 // Region configuration
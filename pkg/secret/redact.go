@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+// redactions maps resolved secret values to their references
+var redactions = struct {
+	sync.RWMutex
+	values map[string]string
+}{
+	values: map[string]string{},
+}
+
+// Register adds resolved value which should be replaced with reference in results
+// Escaped value is also registered because it is used in query of URL.
+func Register(value, ref string) {
+	if len(value) < constants.MinRedactLength {
+		return
+	}
+
+	redactions.Lock()
+	defer redactions.Unlock()
+
+	redactions.values[value] = ref
+	if escaped := url.QueryEscape(value); escaped != value {
+		redactions.values[escaped] = ref
+	}
+}
+
+// Redact replaces every resolved secret value in s with its reference
+func Redact(s string) string {
+	redactions.RLock()
+	defer redactions.RUnlock()
+
+	if len(redactions.values) == 0 || len(s) == 0 {
+		return s
+	}
+
+	// longer values first so that a value containing another one is replaced as a whole
+	values := make([]string, 0, len(redactions.values))
+	for value := range redactions.values {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, redactions.values[value])
+	}
+
+	return s
+}
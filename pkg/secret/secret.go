@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+// reference matches secret reference like `${ssm:/bigshot/token}`, `${secretsmanager:arn#key}` or `${env:TOKEN}`
+var reference = regexp.MustCompile(`\$\{(ssm|secretsmanager|env):([^}#]+)(?:#([^}]+))?\}`)
+
+// Reference is a reference to value in secret store
+type Reference struct {
+	Source string
+
+	// Name is name or ARN of parameter or secret
+	Name string
+
+	// Key is the field of JSON value. Whole value is used if it is empty.
+	Key string
+}
+
+// Resolver looks up value of reference
+type Resolver interface {
+	Lookup(ctx context.Context, ref Reference) (string, error)
+}
+
+// HasReference returns whether value has secret reference
+func HasReference(value string) bool {
+	return reference.MatchString(value)
+}
+
+// References returns every secret reference in value
+func References(value string) []Reference {
+	var refs []Reference
+	for _, matched := range reference.FindAllStringSubmatch(value, -1) {
+		refs = append(refs, Reference{
+			Source: matched[1],
+			Name:   matched[2],
+			Key:    matched[3],
+		})
+	}
+
+	return refs
+}
+
+// Resolve replaces every secret reference in value with the value in secret store
+// Resolved values are registered to be redacted from results.
+func Resolve(ctx context.Context, resolver Resolver, value string) (string, error) {
+	var lookupErr error
+	resolved := reference.ReplaceAllStringFunc(value, func(s string) string {
+		if lookupErr != nil {
+			return s
+		}
+
+		ref := References(s)[0]
+		v, err := resolver.Lookup(ctx, ref)
+		if err != nil {
+			lookupErr = fmt.Errorf("secret %s:%s cannot be resolved: %s", ref.Source, ref.Name, err.Error())
+			return s
+		}
+		Register(v, s)
+
+		return v
+	})
+
+	if lookupErr != nil {
+		return constants.EmptyString, lookupErr
+	}
+
+	return resolved, nil
+}
+
+// DefaultResolver looks up environment variables, SSM parameters and Secrets Manager secrets
+type DefaultResolver struct {
+	// Region is used for names which are not ARN. Defaults to region of the session.
+	Region string
+}
+
+// Lookup returns value of environment variable, parameter or secret
+func (r DefaultResolver) Lookup(ctx context.Context, ref Reference) (string, error) {
+	if ref.Source == constants.EnvSource {
+		value, ok := os.LookupEnv(ref.Name)
+		if !ok {
+			return constants.EmptyString, fmt.Errorf("environment variable is not set: %s", ref.Name)
+		}
+
+		return JSONField(value, ref.Key)
+	}
+
+	region := r.Region
+	if arnRegion := regionOfARN(ref.Name); len(arnRegion) > 0 {
+		region = arnRegion
+	}
+	if len(region) == 0 {
+		region = aws.StringValue(client.GetAwsSession().Config.Region)
+	}
+
+	var value string
+	var err error
+	switch ref.Source {
+	case constants.SSMSource:
+		value, err = client.NewSSMClient(region).GetParameter(ctx, ref.Name)
+	case constants.SecretsManagerSource:
+		value, err = client.NewSecretsManagerClient(region).GetSecretString(ctx, ref.Name)
+	default:
+		err = fmt.Errorf("secret source is not supported: %s", ref.Source)
+	}
+
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	return JSONField(value, ref.Key)
+}
+
+// MemoryStore is an in-memory secret store keyed by `source:name` like `ssm:/bigshot/token`
+type MemoryStore map[string]string
+
+// Lookup returns value of reference in store
+func (m MemoryStore) Lookup(ctx context.Context, ref Reference) (string, error) {
+	value, ok := m[ref.Source+":"+ref.Name]
+	if !ok {
+		return constants.EmptyString, fmt.Errorf("secret does not exist: %s:%s", ref.Source, ref.Name)
+	}
+
+	return JSONField(value, ref.Key)
+}
+
+// JSONField returns field of JSON object value
+// Value is returned as it is if key is empty.
+func JSONField(value, key string) (string, error) {
+	if len(key) == 0 {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return constants.EmptyString, fmt.Errorf("value is not JSON object for key %s", key)
+	}
+
+	field, ok := fields[key]
+	if !ok {
+		return constants.EmptyString, fmt.Errorf("key does not exist: %s", key)
+	}
+
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(field)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	return string(b), nil
+}
+
+// regionOfARN returns region of ARN or empty string if name is not ARN
+func regionOfARN(name string) string {
+	if !strings.HasPrefix(name, "arn:") {
+		return constants.EmptyString
+	}
+
+	parts := strings.SplitN(name, ":", 6)
+	if len(parts) < 6 {
+		return constants.EmptyString
+	}
+
+	return parts[3]
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestResolve(t *testing.T) {
+	resolver := MemoryStore{
+		"ssm:/bigshot/ca": "-----BEGIN CERTIFICATE-----",
+		"secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:mtls-AbCdEf": `{"cert":"CERT","port":8443}`,
+	}
+
+	testData := []struct {
+		Input  string
+		Output string
+		Error  bool
+	}{
+		{Input: "plain", Output: "plain"},
+		{Input: "${ssm:/bigshot/ca}", Output: "-----BEGIN CERTIFICATE-----"},
+		{Input: "${secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:mtls-AbCdEf#cert}", Output: "CERT"},
+		{Input: "port ${secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:mtls-AbCdEf#port}", Output: "port 8443"},
+		{Input: "${secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:mtls-AbCdEf#key}", Error: true},
+		{Input: "${ssm:/bigshot/none}", Error: true},
+	}
+
+	for _, td := range testData {
+		output, err := Resolve(context.Background(), resolver, td.Input)
+		if (err != nil) != td.Error {
+			t.Errorf("%s - expected error: %t, got: %v", td.Input, td.Error, err)
+			continue
+		}
+
+		if output != td.Output {
+			t.Errorf("%s - expected: %s, got: %s", td.Input, td.Output, output)
+		}
+	}
+}
+
+func TestDefaultResolverEnv(t *testing.T) {
+	os.Setenv("BIGSHOT_TEST_TOKEN", "env-token")
+	defer os.Unsetenv("BIGSHOT_TEST_TOKEN")
+
+	output, err := Resolve(context.Background(), DefaultResolver{}, "Bearer ${env:BIGSHOT_TEST_TOKEN}")
+	if err != nil || output != "Bearer env-token" {
+		t.Errorf("expected: Bearer env-token, got: %s, %v", output, err)
+	}
+
+	if _, err := Resolve(context.Background(), DefaultResolver{}, "${env:BIGSHOT_TEST_NONE}"); err == nil {
+		t.Error("expected error of unset environment variable")
+	}
+}
+
+func TestRegionOfARN(t *testing.T) {
+	if region := regionOfARN("arn:aws:ssm:ap-northeast-2:123456789012:parameter/bigshot/ca"); region != "ap-northeast-2" {
+		t.Errorf("expected: ap-northeast-2, got: %s", region)
+	}
+
+	if region := regionOfARN("/bigshot/ca"); region != "" {
+		t.Errorf("expected empty region, got: %s", region)
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	resolver := MemoryStore{
+		"ssm:/bigshot/api-key":         "key-1234",
+		"secretsmanager:bigshot/oauth": `{"id":"client","secret":"s3cr3t/+"}`,
+	}
+
+	target := schema.Target{
+		URL:    aws.String("https://api.example.com"),
+		Header: map[string]string{"X-Api-Key": "${ssm:/bigshot/api-key}"},
+		Query:  map[string]string{"secret": "${secretsmanager:bigshot/oauth#secret}"},
+		Auth: &schema.AuthOption{
+			ClientID:     aws.String("${secretsmanager:bigshot/oauth#id}"),
+			ClientSecret: aws.String("${secretsmanager:bigshot/oauth#secret}"),
+		},
+		Payload: &schema.Payload{
+			JSON: map[interface{}]interface{}{"keys": []interface{}{"${ssm:/bigshot/api-key}"}},
+		},
+		Steps: []schema.Step{{Header: map[string]string{"X-Api-Key": "${ssm:/bigshot/api-key}"}}},
+		TCP:   &schema.TCPOption{Payload: aws.String("AUTH ${ssm:/bigshot/api-key}\r\n")},
+		GRPC:  &schema.GRPCOption{Metadata: map[string]string{"authorization": "Bearer ${ssm:/bigshot/api-key}"}},
+	}
+
+	resolved, err := ResolveTarget(context.Background(), resolver, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved.Header["X-Api-Key"] != "key-1234" || resolved.Steps[0].Header["X-Api-Key"] != "key-1234" {
+		t.Errorf("header is not resolved: %v, %v", resolved.Header, resolved.Steps[0].Header)
+	}
+
+	if aws.StringValue(resolved.Auth.ClientID) != "client" || aws.StringValue(resolved.Auth.ClientSecret) != "s3cr3t/+" {
+		t.Errorf("auth is not resolved: %s, %s", aws.StringValue(resolved.Auth.ClientID), aws.StringValue(resolved.Auth.ClientSecret))
+	}
+
+	if aws.StringValue(resolved.TCP.Payload) != "AUTH key-1234\r\n" || resolved.GRPC.Metadata["authorization"] != "Bearer key-1234" {
+		t.Errorf("tcp payload or grpc metadata is not resolved: %q, %v", aws.StringValue(resolved.TCP.Payload), resolved.GRPC.Metadata)
+	}
+
+	keys := resolved.Payload.JSON.(map[interface{}]interface{})["keys"].([]interface{})
+	if keys[0] != "key-1234" {
+		t.Errorf("payload is not resolved: %v", keys)
+	}
+
+	if target.Header["X-Api-Key"] != "${ssm:/bigshot/api-key}" || aws.StringValue(target.Auth.ClientSecret) != "${secretsmanager:bigshot/oauth#secret}" {
+		t.Error("references of original target are changed")
+	}
+
+	redacted := Redact("GET https://api.example.com?secret=s3cr3t%2F%2B: key-1234 rejected")
+	if redacted != "GET https://api.example.com?secret=${secretsmanager:bigshot/oauth#secret}: ${ssm:/bigshot/api-key} rejected" {
+		t.Errorf("resolved values are not redacted: %s", redacted)
+	}
+
+	if _, err := ResolveTarget(context.Background(), resolver, schema.Target{Header: map[string]string{"X-Api-Key": "${ssm:/bigshot/none}"}}); err == nil {
+		t.Error("expected error of unknown reference")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// ResolveTarget returns copy of target whose header, body, query, payload, auth, tls,
+// steps, websocket message, tcp payload and grpc metadata have secret references resolved
+// Target itself is not changed so that references are kept in templates.
+func ResolveTarget(ctx context.Context, resolver Resolver, target schema.Target) (schema.Target, error) {
+	r := targetResolver{ctx: ctx, resolver: resolver}
+
+	target.Header = r.values(target.Header)
+	target.Body = r.values(target.Body)
+	target.Query = r.values(target.Query)
+	target.Payload = r.payload(target.Payload)
+
+	if target.Auth != nil {
+		option := *target.Auth
+		option.Username = r.value(option.Username)
+		option.Password = r.value(option.Password)
+		option.Token = r.value(option.Token)
+		option.TokenURL = r.value(option.TokenURL)
+		option.ClientID = r.value(option.ClientID)
+		option.ClientSecret = r.value(option.ClientSecret)
+		option.Params = r.values(option.Params)
+		target.Auth = &option
+	}
+
+	if target.TLS != nil {
+		option := *target.TLS
+		option.ClientCert = r.value(option.ClientCert)
+		option.ClientKey = r.value(option.ClientKey)
+		option.CA = r.value(option.CA)
+		target.TLS = &option
+	}
+
+	if target.WebSocket != nil {
+		option := *target.WebSocket
+		option.Message = r.value(option.Message)
+		target.WebSocket = &option
+	}
+
+	if target.TCP != nil {
+		option := *target.TCP
+		option.Payload = r.value(option.Payload)
+		target.TCP = &option
+	}
+
+	if target.GRPC != nil {
+		option := *target.GRPC
+		option.Metadata = r.values(option.Metadata)
+		target.GRPC = &option
+	}
+
+	if len(target.Steps) > 0 {
+		steps := make([]schema.Step, len(target.Steps))
+		for i, step := range target.Steps {
+			step.Header = r.values(step.Header)
+			step.Body = r.values(step.Body)
+			step.Query = r.values(step.Query)
+			step.Payload = r.payload(step.Payload)
+			steps[i] = step
+		}
+		target.Steps = steps
+	}
+
+	if r.err != nil {
+		return schema.Target{}, r.err
+	}
+
+	return target, nil
+}
+
+// targetResolver resolves values of target and keeps the first error
+type targetResolver struct {
+	ctx      context.Context
+	resolver Resolver
+	err      error
+}
+
+// resolve returns resolved string
+func (r *targetResolver) resolve(s string) string {
+	if r.err != nil || !HasReference(s) {
+		return s
+	}
+
+	resolved, err := Resolve(r.ctx, r.resolver, s)
+	if err != nil {
+		r.err = err
+		return s
+	}
+
+	return resolved
+}
+
+// value returns pointer of resolved value
+func (r *targetResolver) value(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	resolved := r.resolve(*s)
+	return &resolved
+}
+
+// values returns copy of map with resolved values
+func (r *targetResolver) values(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	resolved := map[string]string{}
+	for k, v := range m {
+		resolved[k] = r.resolve(v)
+	}
+
+	return resolved
+}
+
+// payload returns copy of payload with resolved values
+func (r *targetResolver) payload(p *schema.Payload) *schema.Payload {
+	if p == nil {
+		return nil
+	}
+
+	payload := *p
+	payload.Raw = r.value(payload.Raw)
+	payload.JSON = r.json(payload.JSON)
+	payload.Form = r.values(payload.Form)
+	if len(payload.Multipart) > 0 {
+		fields := make([]schema.MultipartField, len(payload.Multipart))
+		for i, field := range payload.Multipart {
+			field.Value = r.value(field.Value)
+			fields[i] = field
+		}
+		payload.Multipart = fields
+	}
+
+	return &payload
+}
+
+// json returns copy of JSON value with resolved strings
+// Maps decoded from both YAML and JSON are supported.
+func (r *targetResolver) json(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return r.resolve(value)
+	case []interface{}:
+		resolved := make([]interface{}, len(value))
+		for i, item := range value {
+			resolved[i] = r.json(item)
+		}
+		return resolved
+	case map[string]interface{}:
+		resolved := map[string]interface{}{}
+		for k, item := range value {
+			resolved[k] = r.json(item)
+		}
+		return resolved
+	case map[interface{}]interface{}:
+		resolved := map[interface{}]interface{}{}
+		for k, item := range value {
+			resolved[k] = r.json(item)
+		}
+		return resolved
+	}
+
+	return v
+}
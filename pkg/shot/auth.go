@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/auth"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// AuthorizedHeader returns copy of header with Authorization of auth option
+// Header is returned as it is if there is no auth option.
+func AuthorizedHeader(header map[string]string, option *schema.AuthOption, timeout time.Duration) (map[string]string, error) {
+	if option == nil {
+		return header, nil
+	}
+
+	provider, err := auth.New(*option, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	authorization, err := provider.Authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authorized := map[string]string{}
+	for k, v := range header {
+		authorized[k] = v
+	}
+	authorized["Authorization"] = authorization
+
+	return authorized, nil
+}
+
+// AuthFailure returns failure of getting credentials before request
+func AuthFailure(err error) *schema.Failure {
+	return &schema.Failure{
+		Phase:   constants.PhaseAuth,
+		Reason:  constants.ReasonAuthFailed,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// NewRequestBody returns request body and its content type
+// Body is encoded as JSON object when payload is not set.
+func NewRequestBody(body map[string]string, payload *schema.Payload) (io.Reader, string, error) {
+	if payload == nil {
+		if body == nil {
+			return nil, constants.EmptyString, nil
+		}
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+
+		return bytes.NewReader(b), constants.JSONContentType, nil
+	}
+
+	var data []byte
+	var contentType string
+	switch {
+	case payload.Raw != nil:
+		data, contentType = []byte(*payload.Raw), constants.RawContentType
+	case payload.JSON != nil:
+		b, err := json.Marshal(tools.NormalizeYAML(payload.JSON))
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+		data, contentType = b, constants.JSONContentType
+	case payload.Form != nil:
+		form := url.Values{}
+		for k, v := range payload.Form {
+			form.Set(k, v)
+		}
+		data, contentType = []byte(form.Encode()), constants.FormContentType
+	case payload.Multipart != nil:
+		b, ct, err := multipartBody(payload.Multipart)
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+
+		// boundary in content type is required to parse multipart body
+		return bytes.NewReader(b), ct, nil
+	case payload.File != nil:
+		b, err := ioutil.ReadFile(*payload.File)
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+		data, contentType = b, http.DetectContentType(b)
+	default:
+		return nil, constants.EmptyString, errors.New("one of raw, json, form, multipart and file is required for payload")
+	}
+
+	if payload.ContentType != nil {
+		contentType = *payload.ContentType
+	}
+
+	return bytes.NewReader(data), contentType, nil
+}
+
+// multipartBody encodes fields as multipart/form-data
+func multipartBody(fields []schema.MultipartField) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		name := aws.StringValue(field.Name)
+		if field.File == nil {
+			if err := w.WriteField(name, aws.StringValue(field.Value)); err != nil {
+				return nil, constants.EmptyString, err
+			}
+			continue
+		}
+
+		content, err := ioutil.ReadFile(*field.File)
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+
+		part, err := w.CreateFormFile(name, filepath.Base(*field.File))
+		if err != nil {
+			return nil, constants.EmptyString, err
+		}
+
+		if _, err := part.Write(content); err != nil {
+			return nil, constants.EmptyString, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, constants.EmptyString, err
+	}
+
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// WithQuery adds query parameters to target URL
+func WithQuery(target string, query map[string]string) (string, error) {
+	if len(query) == 0 {
+		return target, nil
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	values := parsed.Query()
+	for k, v := range query {
+		values.Set(k, v)
+	}
+	parsed.RawQuery = values.Encode()
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestNewRequestBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bigshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "payload.xml")
+	if err := ioutil.WriteFile(file, []byte(`<?xml version="1.0"?><order/>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		Name        string
+		Body        map[string]string
+		Payload     *schema.Payload
+		Output      string
+		ContentType string
+		Error       bool
+	}{
+		{
+			Name: "empty",
+		},
+		{
+			Name:        "body",
+			Body:        map[string]string{"id": "1"},
+			Output:      `{"id":"1"}`,
+			ContentType: constants.JSONContentType,
+		},
+		{
+			Name:        "raw",
+			Payload:     &schema.Payload{Raw: aws.String("id=1;name=a")},
+			Output:      "id=1;name=a",
+			ContentType: constants.RawContentType,
+		},
+		{
+			Name: "nested json from yaml",
+			Payload: &schema.Payload{JSON: map[interface{}]interface{}{
+				"order": map[interface{}]interface{}{"id": 1, "items": []interface{}{"a"}},
+			}},
+			Output:      `{"order":{"id":1,"items":["a"]}}`,
+			ContentType: constants.JSONContentType,
+		},
+		{
+			Name:        "form",
+			Payload:     &schema.Payload{Form: map[string]string{"name": "a b", "id": "1"}},
+			Output:      "id=1&name=a+b",
+			ContentType: constants.FormContentType,
+		},
+		{
+			Name:        "file with content type",
+			Payload:     &schema.Payload{File: aws.String(file), ContentType: aws.String("application/xml")},
+			Output:      `<?xml version="1.0"?><order/>`,
+			ContentType: "application/xml",
+		},
+		{
+			Name:    "missing file",
+			Payload: &schema.Payload{File: aws.String(filepath.Join(dir, "missing"))},
+			Error:   true,
+		},
+		{
+			Name:    "empty payload",
+			Payload: &schema.Payload{},
+			Error:   true,
+		},
+	}
+
+	for _, test := range testData {
+		body, contentType, err := NewRequestBody(test.Body, test.Payload)
+		if test.Error != (err != nil) {
+			t.Errorf("%s: error expected: %v, got: %v", test.Name, test.Error, err)
+			continue
+		}
+
+		var output string
+		if body != nil {
+			b, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			output = string(b)
+		}
+
+		if output != test.Output {
+			t.Errorf("%s: expected: %s, got: %s", test.Name, test.Output, output)
+		}
+
+		if contentType != test.ContentType {
+			t.Errorf("%s: expected content type: %s, got: %s", test.Name, test.ContentType, contentType)
+		}
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bigshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "image.png")
+	if err := ioutil.WriteFile(file, []byte("png"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	body, contentType, err := NewRequestBody(nil, &schema.Payload{Multipart: []schema.MultipartField{
+		{Name: aws.String("name"), Value: aws.String("sample")},
+		{Name: aws.String("image"), File: aws.String(file)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("content type is not multipart: %s", contentType)
+	}
+
+	form, err := multipart.NewReader(body, params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := strings.Join(form.Value["name"], ","); value != "sample" {
+		t.Errorf("expected value: sample, got: %s", value)
+	}
+
+	if len(form.File["image"]) != 1 || form.File["image"][0].Filename != "image.png" {
+		t.Errorf("file is not uploaded: %+v", form.File)
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	testData := []struct {
+		Target string
+		Query  map[string]string
+		Output string
+	}{
+		{Target: "https://example.com", Output: "https://example.com"},
+		{Target: "https://example.com/search", Query: map[string]string{"q": "a b"}, Output: "https://example.com/search?q=a+b"},
+		{Target: "http://example.com:8080/items?page=1", Query: map[string]string{"size": "10"}, Output: "http://example.com:8080/items?page=1&size=10"},
+	}
+
+	for _, test := range testData {
+		output, err := WithQuery(test.Target, test.Query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output != test.Output {
+			t.Errorf("expected: %s, got: %s", test.Output, output)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// NewCertificate returns certificate information of leaf certificate
+func NewCertificate(leaf *x509.Certificate, verified bool, verifyErr error, thresholds []int) *schema.Certificate {
+	days := int(math.Floor(time.Until(leaf.NotAfter).Hours() / 24))
+
+	cert := schema.Certificate{
+		Subject:         leaf.Subject.String(),
+		SANs:            leaf.DNSNames,
+		Issuer:          leaf.Issuer.String(),
+		SerialNumber:    fmt.Sprintf("%X", leaf.SerialNumber),
+		NotAfter:        leaf.NotAfter,
+		DaysUntilExpiry: days,
+		ChainVerified:   verified,
+		Threshold:       ExpiryThreshold(days, thresholds),
+	}
+
+	for _, ip := range leaf.IPAddresses {
+		cert.SANs = append(cert.SANs, ip.String())
+	}
+
+	if verifyErr != nil {
+		cert.ChainError = verifyErr.Error()
+	}
+
+	return &cert
+}
+
+// CertificateFromState returns certificate information from TLS connection state
+func CertificateFromState(cs tls.ConnectionState, thresholds []int) *schema.Certificate {
+	if len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+
+	return NewCertificate(cs.PeerCertificates[0], len(cs.VerifiedChains) > 0, nil, thresholds)
+}
+
+// CertificateFromError returns certificate information from certificate verification error
+func CertificateFromError(err error, thresholds []int) *schema.Certificate {
+	var certErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	var leaf *x509.Certificate
+	switch {
+	case errors.As(err, &certErr):
+		leaf, err = certErr.Cert, certErr
+	case errors.As(err, &authorityErr):
+		leaf, err = authorityErr.Cert, authorityErr
+	case errors.As(err, &hostnameErr):
+		leaf, err = hostnameErr.Certificate, hostnameErr
+	}
+
+	if leaf == nil {
+		return nil
+	}
+
+	return NewCertificate(leaf, false, err, thresholds)
+}
+
+// ExpiryThreshold returns the threshold in days which is crossed today
+// so that each threshold is alarmed only on the day it is reached. 0 means no threshold is crossed.
+func ExpiryThreshold(days int, thresholds []int) int {
+	for _, threshold := range thresholds {
+		if days == threshold {
+			return threshold
+		}
+	}
+
+	return 0
+}
+
+// certificateBlock returns slack block of certificate
+func certificateBlock(cert *schema.Certificate) slacker.Block {
+	lines := []string{
+		fmt.Sprintf("*Certificate*: %s", cert.Subject),
+		fmt.Sprintf("*Issuer*: %s", cert.Issuer),
+		fmt.Sprintf("*Serial*: `%s`", cert.SerialNumber),
+		fmt.Sprintf("*SANs*: %s", strings.Join(cert.SANs, ", ")),
+		fmt.Sprintf("*Not After*: %s (%d days left)", cert.NotAfter.Format(time.RFC3339), cert.DaysUntilExpiry),
+		fmt.Sprintf("*Chain Verified*: %t", cert.ChainVerified),
+	}
+
+	if len(cert.ChainError) > 0 {
+		lines = append(lines, fmt.Sprintf("*Chain Error*: %s", cert.ChainError))
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// sendCertificateAlarm sends alarm of certificate which expires soon
+func sendCertificateAlarm(slackURLs []string, target, region string, cert *schema.Certificate) error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("Certificate expires within %d days: `%s`", cert.Threshold, target),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", region),
+		},
+	})
+
+	blocks = append(blocks, certificateBlock(cert))
+
+	attachments = append(attachments, slacker.Attachment{
+		Color: constants.WarningColor,
+		Text:  fmt.Sprintf("*%d days* left until %s", cert.DaysUntilExpiry, cert.NotAfter.Format(time.RFC3339)),
+	})
+
+	return sendMessage(slackURLs, attachments, blocks)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestCertificate returns self-signed certificate which expires after d
+func newTestCertificate(t *testing.T, d time.Duration) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(0xBEEF),
+		Subject:               pkix.Name{CommonName: "api.example.com"},
+		DNSNames:              []string{"api.example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(d),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func TestExpiryThreshold(t *testing.T) {
+	thresholds := []int{30, 14, 7}
+	testData := []struct {
+		Days   int
+		Output int
+	}{
+		{Days: 45, Output: 0},
+		{Days: 30, Output: 30},
+		{Days: 29, Output: 0},
+		{Days: 14, Output: 14},
+		{Days: 10, Output: 0},
+		{Days: 7, Output: 7},
+		{Days: 3, Output: 0},
+	}
+
+	for _, td := range testData {
+		if output := ExpiryThreshold(td.Days, thresholds); output != td.Output {
+			t.Errorf("%d days - expected: %d, got: %d", td.Days, td.Output, output)
+		}
+	}
+}
+
+func TestNewCertificate(t *testing.T) {
+	testData := []struct {
+		Name      string
+		Duration  time.Duration
+		Days      int
+		Threshold int
+	}{
+		{Name: "far from expiry", Duration: 90*24*time.Hour + time.Hour, Days: 90, Threshold: 0},
+		{Name: "threshold day", Duration: 14*24*time.Hour + time.Hour, Days: 14, Threshold: 14},
+		{Name: "expired", Duration: -(2*24*time.Hour - time.Hour), Days: -2, Threshold: 0},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			leaf := newTestCertificate(t, td.Duration)
+			cert := NewCertificate(leaf, true, nil, []int{30, 14, 7})
+
+			if cert.DaysUntilExpiry != td.Days || cert.Threshold != td.Threshold {
+				t.Errorf("expected days: %d, threshold: %d, got days: %d, threshold: %d", td.Days, td.Threshold, cert.DaysUntilExpiry, cert.Threshold)
+			}
+
+			if cert.SerialNumber != "BEEF" || !cert.ChainVerified || len(cert.ChainError) > 0 {
+				t.Errorf("unexpected certificate: %+v", cert)
+			}
+
+			if len(cert.SANs) != 2 || cert.SANs[0] != "api.example.com" || cert.SANs[1] != "127.0.0.1" {
+				t.Errorf("SANs should have DNS names and IPs: %v", cert.SANs)
+			}
+		})
+	}
+}
+
+func TestCertificateFromError(t *testing.T) {
+	leaf := newTestCertificate(t, 24*time.Hour+time.Hour)
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+
+	_, unknownAuthority := leaf.Verify(x509.VerifyOptions{DNSName: "api.example.com"})
+	_, expired := leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: time.Now().Add(48 * time.Hour)})
+	_, hostname := leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "www.example.com"})
+
+	testData := []struct {
+		Name  string
+		Err   error
+		Found bool
+	}{
+		{Name: "unknown authority", Err: unknownAuthority, Found: true},
+		{Name: "expired", Err: expired, Found: true},
+		{Name: "hostname mismatch wrapped", Err: fmt.Errorf("Get https://www.example.com: %w", hostname), Found: true},
+		{Name: "not certificate error", Err: errors.New("connection refused"), Found: false},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			cert := CertificateFromError(td.Err, []int{1})
+			if !td.Found {
+				if cert != nil {
+					t.Errorf("expected no certificate, got: %+v", cert)
+				}
+				return
+			}
+
+			if cert == nil {
+				t.Fatalf("certificate is not found in error: %v", td.Err)
+			}
+
+			if cert.ChainVerified || len(cert.ChainError) == 0 {
+				t.Errorf("chain should not be verified with error: %+v", cert)
+			}
+
+			if cert.DaysUntilExpiry != 1 || cert.Threshold != 1 {
+				t.Errorf("expected 1 day with threshold 1, got: %d, %d", cert.DaysUntilExpiry, cert.Threshold)
+			}
+		})
+	}
+}
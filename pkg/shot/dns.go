@@ -0,0 +1,412 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+type DNS struct {
+	Name       string
+	Target     string
+	RecordType string
+	Nameserver string
+	Option     schema.DNSOption
+	Timeout    time.Duration
+	Region     string
+	SlackURL   []string
+	LogLevel   string
+	Result     schema.Result
+}
+
+// NewDNS creates DNS resolution test
+func NewDNS(region string) Shooter {
+	return &DNS{
+		Name:       fmt.Sprintf("Request from %s", region),
+		RecordType: constants.DefaultRecordType,
+		Timeout:    time.Duration(constants.DefaultTargetTimeout) * time.Second,
+		Region:     region,
+		Target:     constants.EmptyString,
+	}
+}
+
+// SetSlackURL set slack URL for notification
+func (d *DNS) SetSlackURL(s []string) {
+	d.SlackURL = s
+}
+
+// SetRate is not used for DNS
+func (d *DNS) SetRate(freq int) {}
+
+// SetTimeout sets timeout of DNS query
+func (d *DNS) SetTimeout(i int) {
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	d.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
+}
+
+// SetLogLevel sets loglevel
+func (d *DNS) SetLogLevel(logLevel string) {
+	d.LogLevel = logLevel
+}
+
+// SetTarget sets target domain to resolve
+func (d *DNS) SetTarget(url, port string) {
+	d.Target = parseHost(url)
+	logrus.Infof("Target: %s, Protocol: DNS", d.Target)
+}
+
+// SetMethod is not used for DNS
+func (d *DNS) SetMethod(s string) {}
+
+// SetBody is not used for DNS
+func (d *DNS) SetBody(m map[string]string) {}
+
+// SetHeader is not used for DNS
+func (d *DNS) SetHeader(m map[string]string) {}
+
+// SetOption sets DNS specific options
+func (d *DNS) SetOption(option schema.DNSOption) {
+	d.Option = option
+
+	if option.RecordType != nil && len(*option.RecordType) > 0 {
+		d.RecordType = strings.ToUpper(*option.RecordType)
+	}
+
+	if option.Nameserver != nil {
+		d.Nameserver = *option.Nameserver
+	}
+}
+
+// GetNameserver returns address of nameserver to query
+func (d *DNS) GetNameserver() (string, error) {
+	if len(d.Nameserver) > 0 {
+		if _, _, err := net.SplitHostPort(d.Nameserver); err == nil {
+			return d.Nameserver, nil
+		}
+		return net.JoinHostPort(strings.Trim(d.Nameserver, "[]"), constants.DefaultDNSPort), nil
+	}
+
+	config, err := dns.ClientConfigFromFile(constants.ResolvConfPath)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	if len(config.Servers) == 0 {
+		return constants.EmptyString, fmt.Errorf("no nameserver is configured in %s", constants.ResolvConfPath)
+	}
+
+	return net.JoinHostPort(config.Servers[0], config.Port), nil
+}
+
+// Resolve queries the record of target to nameserver
+func (d *DNS) Resolve() error {
+	qtype, ok := dns.StringToType[d.RecordType]
+	if !ok {
+		return fmt.Errorf("record type is not supported: %s", d.RecordType)
+	}
+
+	nameserver, err := d.GetNameserver()
+	if err != nil {
+		return err
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(d.Target), qtype)
+	msg.RecursionDesired = true
+
+	c := &dns.Client{Timeout: d.Timeout}
+	resp, rtt, err := c.Exchange(msg, nameserver)
+	if err != nil {
+		d.SetFailure(nameserver, rtt, err)
+		return nil
+	}
+
+	// retry with TCP if the answer does not fit in UDP packet
+	if resp.Truncated {
+		c.Net = "tcp"
+		if resp, rtt, err = c.Exchange(msg, nameserver); err != nil {
+			d.SetFailure(nameserver, rtt, err)
+			return nil
+		}
+	}
+
+	d.SetResult(nameserver, rtt, resp)
+
+	return nil
+}
+
+// Run starts DNS resolution test
+func (d *DNS) Run() error {
+	if err := d.Resolve(); err != nil {
+		if sendErr := SendErrorAlarm(d.SlackURL, d.Target, d.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&d.Result)
+
+	if d.LogLevel == "debug" {
+		if err := d.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(d.SlackURL) > 0 && d.Result.Failed() {
+		if err := d.SendAlarm(); err != nil {
+			return err
+		}
+	}
+
+	if err := d.SaveData(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunWithResult runs DNS resolution test and returns result
+func (d *DNS) RunWithResult() (*schema.Result, error) {
+	if err := d.Resolve(); err != nil {
+		return nil, err
+	}
+	RedactResult(&d.Result)
+
+	return &d.Result, nil
+}
+
+// SetResult sets the DNS response to DNS.Result
+func (d *DNS) SetResult(nameserver string, rtt time.Duration, resp *dns.Msg) {
+	result := schema.DNSResult{
+		Name:           d.Target,
+		RecordType:     d.RecordType,
+		Nameserver:     nameserver,
+		Rcode:          dns.RcodeToString[resp.Rcode],
+		ResolutionTime: rtt,
+	}
+
+	for _, rr := range resp.Answer {
+		result.Answers = append(result.Answers, schema.DNSAnswer{
+			Type:  dns.TypeToString[rr.Header().Rrtype],
+			Value: RecordValue(rr),
+			TTL:   rr.Header().Ttl,
+		})
+	}
+
+	d.Result = schema.Result{
+		DNS:        &result,
+		Assertions: checker.AssertDNS(d.Option, result),
+	}
+
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		d.Result.Failure = &schema.Failure{
+			Phase:   constants.PhaseDNS,
+			Reason:  constants.ReasonDNSError,
+			Message: fmt.Sprintf("nameserver returns %s", result.Rcode),
+		}
+	}
+}
+
+// SetFailure sets the result of failed DNS query
+func (d *DNS) SetFailure(nameserver string, rtt time.Duration, err error) {
+	failure := schema.Failure{
+		Phase:   constants.PhaseDNS,
+		Reason:  constants.ReasonDNSError,
+		Message: err.Error(),
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		failure.Reason = constants.ReasonDNSTimeout
+	}
+	logrus.Errorf("DNS query failed: %s", failure.Reason)
+
+	d.Result = schema.Result{
+		DNS: &schema.DNSResult{
+			Name:           d.Target,
+			RecordType:     d.RecordType,
+			Nameserver:     nameserver,
+			ResolutionTime: rtt,
+		},
+		Failure: &failure,
+	}
+}
+
+// RecordValue returns the value of resource record without header
+func RecordValue(rr dns.RR) string {
+	switch r := rr.(type) {
+	case *dns.A:
+		return r.A.String()
+	case *dns.AAAA:
+		return r.AAAA.String()
+	case *dns.CNAME:
+		return r.Target
+	case *dns.NS:
+		return r.Ns
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", r.Preference, r.Mx)
+	case *dns.TXT:
+		return strings.Join(r.Txt, "")
+	case *dns.CAA:
+		return fmt.Sprintf("%d %s \"%s\"", r.Flag, r.Tag, r.Value)
+	}
+
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// PrintResult prints result
+func (d *DNS) PrintResult() error {
+	var scanData = struct {
+		Summary schema.DNSResult
+		Failure *schema.Failure
+	}{
+		Summary: *d.Result.DNS,
+		Failure: d.Result.Failure,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate": color.DecorateAttr,
+		"format":   tools.Formatting,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.DNSTemplate))
+
+	if err := tt.Execute(w, scanData); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(d.Result.DNS.Answers) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "Value", "TTL"})
+		table.SetAutoWrapText(false)
+		for _, answer := range d.Result.DNS.Answers {
+			table.Append([]string{answer.Type, answer.Value, fmt.Sprintf("%d", answer.TTL)})
+		}
+		table.SetRowLine(true)
+		table.Render()
+	}
+
+	DrawAssertionTable(d.Result.Assertions)
+
+	return nil
+}
+
+// SendAlarm sends slack alarm
+func (d *DNS) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	result := d.Result.DNS
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", d.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Domain*: `%s` (%s)", result.Name, result.RecordType),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Nameserver*: `%s`", result.Nameserver),
+		},
+	})
+
+	if failure := d.Result.Failure; failure != nil {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Failure*: `%s` in %s phase\n%s", failure.Reason, failure.Phase, failure.Message),
+			},
+		})
+	}
+
+	if len(d.Result.Assertions) > 0 {
+		blocks = append(blocks, assertionBlock(d.Result.Assertions))
+	}
+
+	var answers []string
+	for _, answer := range result.Answers {
+		answers = append(answers, fmt.Sprintf("%s `%s` (TTL %d)", answer.Type, answer.Value, answer.TTL))
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color: constants.ErrorColor,
+		Text:  fmt.Sprintf("*DNS resolution result* - Resolution Time: %s (%s)", result.ResolutionTime.String(), result.Rcode),
+		Fields: []slacker.Field{
+			{
+				Title: "Answers",
+				Value: strings.Join(answers, "\n"),
+			},
+		},
+	})
+
+	return sendMessage(d.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (d *DNS) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WriteDNSData("bigshot", "synthetics", d.Region, d.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
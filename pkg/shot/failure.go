@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// ClassifyError classifies request error with the progress of tracing data
+func ClassifyError(err error, td schema.TracingData) *schema.Failure {
+	phase := GetFailedPhase(td)
+	failure := schema.Failure{
+		Phase:   phase,
+		Reason:  constants.ReasonUnknown,
+		Message: err.Error(),
+	}
+
+	var dnsErr *net.DNSError
+	var certErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		failure.Phase = constants.PhaseDNS
+		switch {
+		case dnsErr.IsNotFound:
+			failure.Reason = constants.ReasonDNSNXDomain
+		case dnsErr.IsTimeout:
+			failure.Reason = constants.ReasonDNSTimeout
+		default:
+			failure.Reason = constants.ReasonDNSError
+		}
+	case errors.As(err, &certErr):
+		failure.Phase = constants.PhaseTLS
+		failure.Reason = constants.ReasonTLSError
+		if certErr.Reason == x509.Expired {
+			failure.Reason = constants.ReasonTLSExpiredCert
+		}
+	case errors.As(err, &authorityErr):
+		failure.Phase = constants.PhaseTLS
+		failure.Reason = constants.ReasonTLSUnknownAuthority
+	case errors.As(err, &hostnameErr):
+		failure.Phase = constants.PhaseTLS
+		failure.Reason = constants.ReasonTLSHostnameMismatch
+	case isTLSAlert(err):
+		// server rejected handshake, e.g. missing or untrusted client certificate
+		failure.Phase = constants.PhaseTLS
+		failure.Reason = constants.ReasonTLSError
+	case errors.Is(err, syscall.ECONNREFUSED):
+		failure.Phase = constants.PhaseTCP
+		failure.Reason = constants.ReasonTCPRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		failure.Reason = constants.ReasonTCPReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		failure.Phase = constants.PhaseTCP
+		failure.Reason = constants.ReasonTCPUnreachable
+	case isTimeout(err):
+		failure.Reason = timeoutReason(phase)
+	default:
+		failure.Reason = errorReason(phase)
+	}
+
+	return &failure
+}
+
+// ClassifyAssertions returns failure of response from failed assertions
+func ClassifyAssertions(assertions []schema.Assertion) *schema.Failure {
+	for _, assertion := range assertions {
+		if !assertion.Passed && assertion.Name == "status" {
+			return &schema.Failure{
+				Phase:   constants.PhaseHTTP,
+				Reason:  constants.ReasonHTTPStatus,
+				Message: "status code is " + assertion.Actual,
+			}
+		}
+	}
+
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			return &schema.Failure{
+				Phase:   constants.PhaseHTTP,
+				Reason:  constants.ReasonCheckFailed,
+				Message: assertion.Name + " check failed",
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFailedPhase returns the phase which is not finished in tracing data
+func GetFailedPhase(td schema.TracingData) string {
+	switch {
+	case !td.DNSStart.IsZero() && td.DNSDone.IsZero():
+		return constants.PhaseDNS
+	case !td.ConnectionStart.IsZero() && td.ConnectionDone.IsZero():
+		return constants.PhaseTCP
+	case !td.TLSHandshakeStart.IsZero() && td.TLSHandshakeDone.IsZero():
+		return constants.PhaseTLS
+	case !td.GetFirstResponseBtye.IsZero():
+		return constants.PhaseContent
+	case !td.GotConn.IsZero():
+		return constants.PhaseFirstByte
+	case td.ConnectionStart.IsZero():
+		return constants.PhaseDNS
+	}
+
+	return constants.PhaseTCP
+}
+
+// isTimeout checks whether any error in the chain is caused by timeout
+func isTimeout(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTLSAlert checks whether error is an alert sent by the remote peer during handshake
+func isTLSAlert(err error) bool {
+	return strings.Contains(err.Error(), "remote error: tls:")
+}
+
+// timeoutReason returns the reason of timeout in phase
+func timeoutReason(phase string) string {
+	switch phase {
+	case constants.PhaseDNS:
+		return constants.ReasonDNSTimeout
+	case constants.PhaseTCP:
+		return constants.ReasonTCPTimeout
+	case constants.PhaseTLS:
+		return constants.ReasonTLSTimeout
+	case constants.PhaseContent:
+		return constants.ReasonTimeoutContent
+	}
+
+	return constants.ReasonTimeoutFirstByte
+}
+
+// errorReason returns the general reason of error in phase
+func errorReason(phase string) string {
+	switch phase {
+	case constants.PhaseDNS:
+		return constants.ReasonDNSError
+	case constants.PhaseTCP:
+		return constants.ReasonTCPError
+	case constants.PhaseTLS:
+		return constants.ReasonTLSError
+	case constants.PhaseContent:
+		return constants.ReasonContentError
+	}
+
+	return constants.ReasonUnknown
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	now := time.Now()
+	connected := schema.TracingData{
+		DNSStart:        now,
+		DNSDone:         now,
+		ConnectionStart: now,
+		ConnectionDone:  now,
+		GotConn:         now,
+	}
+
+	testData := []struct {
+		Name   string
+		Err    error
+		Data   schema.TracingData
+		Phase  string
+		Reason string
+	}{
+		{
+			Name:   "nxdomain",
+			Err:    wrap(&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}),
+			Data:   schema.TracingData{DNSStart: now},
+			Phase:  constants.PhaseDNS,
+			Reason: constants.ReasonDNSNXDomain,
+		},
+		{
+			Name:   "refused",
+			Err:    wrap(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}),
+			Data:   schema.TracingData{ConnectionStart: now},
+			Phase:  constants.PhaseTCP,
+			Reason: constants.ReasonTCPRefused,
+		},
+		{
+			Name:   "connect timeout",
+			Err:    wrap(&net.OpError{Op: "dial", Err: timeoutError{}}),
+			Data:   schema.TracingData{ConnectionStart: now},
+			Phase:  constants.PhaseTCP,
+			Reason: constants.ReasonTCPTimeout,
+		},
+		{
+			Name:   "expired certificate",
+			Err:    wrap(x509.CertificateInvalidError{Reason: x509.Expired}),
+			Data:   schema.TracingData{ConnectionStart: now, ConnectionDone: now, TLSHandshakeStart: now},
+			Phase:  constants.PhaseTLS,
+			Reason: constants.ReasonTLSExpiredCert,
+		},
+		{
+			Name:   "unknown authority",
+			Err:    wrap(x509.UnknownAuthorityError{}),
+			Data:   schema.TracingData{ConnectionStart: now, ConnectionDone: now, TLSHandshakeStart: now},
+			Phase:  constants.PhaseTLS,
+			Reason: constants.ReasonTLSUnknownAuthority,
+		},
+		{
+			Name:   "first byte timeout",
+			Err:    wrap(timeoutError{}),
+			Data:   connected,
+			Phase:  constants.PhaseFirstByte,
+			Reason: constants.ReasonTimeoutFirstByte,
+		},
+		{
+			Name:   "client certificate rejected",
+			Err:    wrap(errors.New("remote error: tls: certificate required")),
+			Data:   connected,
+			Phase:  constants.PhaseTLS,
+			Reason: constants.ReasonTLSError,
+		},
+		{
+			Name:   "unknown",
+			Err:    errors.New("something wrong"),
+			Data:   connected,
+			Phase:  constants.PhaseFirstByte,
+			Reason: constants.ReasonUnknown,
+		},
+	}
+
+	for _, td := range testData {
+		failure := ClassifyError(td.Err, td.Data)
+		if failure.Phase != td.Phase || failure.Reason != td.Reason {
+			t.Errorf("%s - expected: %s/%s / output: %s/%s", td.Name, td.Phase, td.Reason, failure.Phase, failure.Reason)
+		}
+	}
+}
+
+// wrap wraps error like http client does
+func wrap(err error) error {
+	return &url.Error{Op: "Get", URL: "http://example.com", Err: fmt.Errorf("request: %w", err)}
+}
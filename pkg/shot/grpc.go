@@ -0,0 +1,503 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+type GRPC struct {
+	Name     string
+	Host     string
+	Port     string
+	Service  string
+	TLS      bool
+	Options  *schema.TLSOption
+	Metadata map[string]string
+	Watch    bool
+	Timeout  time.Duration
+	Region   string
+	SlackURL []string
+	LogLevel string
+	Result   schema.Result
+}
+
+// NewGRPC creates gRPC health checking test
+func NewGRPC(region string) Shooter {
+	return &GRPC{
+		Name:    fmt.Sprintf("Request from %s", region),
+		Timeout: time.Duration(constants.DefaultTargetTimeout) * time.Second,
+		Region:  region,
+	}
+}
+
+// SetSlackURL set slack URL for notification
+func (g *GRPC) SetSlackURL(s []string) {
+	g.SlackURL = s
+}
+
+// SetRate is not used for gRPC
+func (g *GRPC) SetRate(freq int) {}
+
+// SetTimeout sets deadline of whole gRPC health checking
+func (g *GRPC) SetTimeout(i int) {
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	g.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
+}
+
+// SetLogLevel sets loglevel
+func (g *GRPC) SetLogLevel(logLevel string) {
+	g.LogLevel = logLevel
+}
+
+// SetTarget sets target host and port
+func (g *GRPC) SetTarget(url, port string) {
+	g.Host = parseHost(url)
+	g.Port = port
+	g.TLS = port == "443"
+	logrus.Infof("Target: %s, Protocol: gRPC", g.Address())
+}
+
+// SetMethod is not used for gRPC
+func (g *GRPC) SetMethod(s string) {}
+
+// SetBody is not used for gRPC
+func (g *GRPC) SetBody(m map[string]string) {}
+
+// SetHeader sets metadata of health checking request
+func (g *GRPC) SetHeader(m map[string]string) {
+	g.Metadata = m
+}
+
+// SetOption sets gRPC specific options
+func (g *GRPC) SetOption(option schema.GRPCOption) {
+	if option.Service != nil {
+		g.Service = *option.Service
+	}
+
+	if option.TLS != nil {
+		g.TLS = *option.TLS
+	}
+
+	// metadata option is merged so that metadata from header is kept
+	if len(option.Metadata) > 0 {
+		merged := map[string]string{}
+		for k, v := range g.Metadata {
+			merged[k] = v
+		}
+		for k, v := range option.Metadata {
+			merged[k] = v
+		}
+		g.Metadata = merged
+	}
+
+	if option.Watch != nil {
+		g.Watch = *option.Watch
+	}
+}
+
+// SetTLSOption sets TLS option and enables TLS
+func (g *GRPC) SetTLSOption(option schema.TLSOption) {
+	g.Options = &option
+	g.TLS = true
+}
+
+// Address returns host and port of target
+func (g *GRPC) Address() string {
+	return net.JoinHostPort(g.Host, g.Port)
+}
+
+// Check calls health checking service of target
+func (g *GRPC) Check() error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.Timeout)
+	defer cancel()
+
+	result := schema.GRPCResult{
+		Address: g.Address(),
+		Service: g.Service,
+		TLS:     g.TLS,
+	}
+
+	// dialer runs in the goroutine of gRPC client
+	var mu sync.Mutex
+	var dialErr error
+	lastDial := schema.TracingData{
+		URL: g.Address(),
+	}
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		dialed := schema.TracingData{URL: g.Address()}
+		conn, err := DialTCP(ctx, g.Host, g.Port, &dialed)
+
+		mu.Lock()
+		defer mu.Unlock()
+		lastDial, dialErr = dialed, err
+
+		return conn, err
+	}
+
+	creds := insecure.NewCredentials()
+	if g.TLS {
+		tlsConfig, err := NewTLSConfig(g.Options)
+		if err != nil {
+			g.SetFailure(lastDial, result, TLSConfigFailure(err))
+			return nil
+		}
+		tlsConfig.ServerName = g.Host
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	start := time.Now()
+	conn, err := grpc.DialContext(ctx, g.Address(),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(dialer),
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithReturnConnectionError(),
+	)
+	result.ConnectTime = time.Since(start)
+
+	// gRPC client may keep redialing after DialContext returns
+	mu.Lock()
+	td, cause := lastDial, dialErr
+	mu.Unlock()
+
+	if err != nil {
+		g.SetFailure(td, result, g.classifyDialError(td, cause, err))
+		return nil
+	}
+	defer conn.Close()
+
+	td.GotConn = time.Now()
+
+	if len(g.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(g.Metadata))
+	}
+
+	health := healthpb.NewHealthClient(conn)
+	request := &healthpb.HealthCheckRequest{Service: g.Service}
+
+	resp, err := health.Check(ctx, request)
+	td.GetFirstResponseBtye = time.Now()
+	result.RPCLatency = td.GetFirstResponseBtye.Sub(td.GotConn)
+	if err != nil {
+		g.SetFailure(td, result, rpcFailure(err))
+		return nil
+	}
+	result.Status = resp.Status.String()
+
+	assertions := []schema.Assertion{servingAssertion("health status", result.Status)}
+
+	if g.Watch {
+		watchStart := time.Now()
+		stream, err := health.Watch(ctx, request)
+		if err == nil {
+			resp, err = stream.Recv()
+		}
+		result.WatchLatency = time.Since(watchStart)
+
+		if err != nil {
+			g.SetFailure(td, result, rpcFailure(err))
+			return nil
+		}
+		result.WatchStatus = resp.Status.String()
+		assertions = append(assertions, servingAssertion("watch status", result.WatchStatus))
+	}
+	td.FinishRequest = time.Now()
+
+	g.Result = schema.Result{
+		TracingData: Calculated(td, false),
+		GRPC:        &result,
+		Assertions:  assertions,
+	}
+
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			g.Result.Failure = &schema.Failure{
+				Phase:   constants.PhaseRPC,
+				Reason:  constants.ReasonGRPCNotServing,
+				Message: fmt.Sprintf("%s is %s", assertion.Name, assertion.Actual),
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// classifyDialError classifies error from connecting to gRPC server
+func (g *GRPC) classifyDialError(td schema.TracingData, dialErr, err error) *schema.Failure {
+	if dialErr != nil {
+		return ClassifyError(dialErr, td)
+	}
+
+	// TCP connection is established but handshake failed
+	phase := constants.PhaseRPC
+	if g.TLS {
+		phase = constants.PhaseTLS
+	}
+
+	reason := errorReason(phase)
+	if isTimeout(err) || (ctxTimeout(err) && !strings.Contains(err.Error(), "connection error")) {
+		reason = timeoutReason(phase)
+	}
+
+	return &schema.Failure{
+		Phase:   phase,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
+
+// SetFailure sets the result of failed health checking with timings up to the failure
+func (g *GRPC) SetFailure(td schema.TracingData, result schema.GRPCResult, failure *schema.Failure) {
+	td.FinishRequest = time.Now()
+	logrus.Errorf("gRPC health checking failed in %s phase: %s", failure.Phase, failure.Reason)
+
+	g.Result = schema.Result{
+		TracingData: Calculated(td, false),
+		GRPC:        &result,
+		Failure:     failure,
+	}
+}
+
+// rpcFailure returns failure of gRPC call with status code
+func rpcFailure(err error) *schema.Failure {
+	code := status.Code(err)
+
+	return &schema.Failure{
+		Phase:   constants.PhaseRPC,
+		Reason:  constants.ReasonGRPCPrefix + toSnakeCase(code.String()),
+		Message: err.Error(),
+	}
+}
+
+// servingAssertion checks whether health status is SERVING
+func servingAssertion(name, actual string) schema.Assertion {
+	expected := healthpb.HealthCheckResponse_SERVING.String()
+
+	return schema.Assertion{
+		Name:     name,
+		Expected: expected,
+		Actual:   actual,
+		Passed:   actual == expected,
+	}
+}
+
+// ctxTimeout checks whether error is caused by deadline of context
+// gRPC client wraps it with the last connection error as string
+func ctxTimeout(err error) bool {
+	return strings.Contains(err.Error(), context.DeadlineExceeded.Error())
+}
+
+// toSnakeCase changes CamelCase to snake_case
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Run starts gRPC health checking
+func (g *GRPC) Run() error {
+	if err := g.Check(); err != nil {
+		if sendErr := SendErrorAlarm(g.SlackURL, g.Address(), g.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&g.Result)
+
+	if g.LogLevel == "debug" {
+		if err := g.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(g.SlackURL) > 0 && g.Result.Failed() {
+		if err := g.SendAlarm(); err != nil {
+			return err
+		}
+	}
+
+	if err := g.SaveData(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunWithResult runs gRPC health checking and returns result
+func (g *GRPC) RunWithResult() (*schema.Result, error) {
+	if err := g.Check(); err != nil {
+		return nil, err
+	}
+	RedactResult(&g.Result)
+
+	return &g.Result, nil
+}
+
+// PrintResult prints result
+func (g *GRPC) PrintResult() error {
+	var scanData = struct {
+		Summary schema.Result
+	}{
+		Summary: g.Result,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate": color.DecorateAttr,
+		"format":   tools.Formatting,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.GRPCTemplate))
+
+	if err := tt.Execute(w, scanData); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	DrawTimingTable([]string{"DNS Lookup", "TCP Connection", "Connect", "RPC Latency", "Total"}, []time.Duration{
+		g.Result.TracingData.DNSLookup,
+		g.Result.TracingData.TCPConnection,
+		g.Result.GRPC.ConnectTime,
+		g.Result.GRPC.RPCLatency,
+		g.Result.TracingData.Total,
+	})
+	DrawAssertionTable(g.Result.Assertions)
+
+	return nil
+}
+
+// SendAlarm sends slack alarm
+func (g *GRPC) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	result := g.Result.GRPC
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", g.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Address*: `%s` (service: `%s`)", result.Address, result.Service),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Connect IP*: `%s`", g.Result.TracingData.ConnectAddr),
+		},
+	})
+
+	if failure := g.Result.Failure; failure != nil {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Failure*: `%s` in %s phase\n%s", failure.Reason, failure.Phase, failure.Message),
+			},
+		})
+	}
+
+	if len(g.Result.Assertions) > 0 {
+		blocks = append(blocks, assertionBlock(g.Result.Assertions))
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color: constants.ErrorColor,
+		Text:  fmt.Sprintf("*gRPC health checking result* - Total Time: %s", g.Result.TracingData.Total.String()),
+		Fields: []slacker.Field{
+			{
+				Title: "Connect",
+				Value: result.ConnectTime.String(),
+				Short: true,
+			},
+			{
+				Title: "RPC Latency",
+				Value: result.RPCLatency.String(),
+				Short: true,
+			},
+		},
+	})
+
+	return sendMessage(g.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (g *GRPC) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WriteGRPCData("bigshot", "synthetics", g.Region, g.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
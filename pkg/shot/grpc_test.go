@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestGRPCCheck(t *testing.T) {
+	// metadata of the last health check is recorded by interceptor
+	var mu sync.Mutex
+	var received metadata.MD
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		mu.Lock()
+		received, _ = metadata.FromIncomingContext(ctx)
+		mu.Unlock()
+		return handler(ctx, req)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("bigshot.Serving", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("bigshot.NotServing", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	testData := []struct {
+		Name     string
+		Service  string
+		Metadata map[string]string
+		Status   string
+		Reason   string
+	}{
+		{Name: "serving", Service: "bigshot.Serving", Status: "SERVING"},
+		{Name: "not serving", Service: "bigshot.NotServing", Status: "NOT_SERVING", Reason: constants.ReasonGRPCNotServing},
+		{Name: "unknown service", Service: "bigshot.Unknown", Reason: constants.ReasonGRPCPrefix + "not_found"},
+		{Name: "metadata forwarded", Service: "bigshot.Serving", Metadata: map[string]string{"authorization": "Bearer token"}, Status: "SERVING"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			g := NewGRPC("test").(*GRPC)
+			g.SetTarget(host, port)
+			g.SetHeader(td.Metadata)
+			g.SetOption(schema.GRPCOption{Service: aws.String(td.Service)})
+
+			if err := g.Check(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := g.Result
+			if result.GRPC.Status != td.Status {
+				t.Errorf("expected status: %s, got: %s", td.Status, result.GRPC.Status)
+			}
+
+			if len(td.Reason) == 0 && result.Failed() {
+				t.Errorf("expected success, got: %+v", result.Failure)
+			}
+
+			if len(td.Reason) > 0 && (result.Failure == nil || result.Failure.Reason != td.Reason) {
+				t.Errorf("expected failure reason: %s, got: %+v", td.Reason, result.Failure)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for k, v := range td.Metadata {
+				if values := received.Get(k); len(values) != 1 || values[0] != v {
+					t.Errorf("metadata %s is not forwarded: %v", k, values)
+				}
+			}
+		})
+	}
+}
@@ -18,100 +18,348 @@ package shot
 
 import (
 	"fmt"
+	"html/template"
 	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/go-ping/ping"
+	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
 
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
 )
 
 type Ping struct {
-	Name     string
-	Attacker *ping.Pinger
-	Rate     int
-	Duration time.Duration
-	Target   string
-	SlackURL []string
+	Name          string
+	Rate          int
+	Interval      time.Duration
+	Timeout       time.Duration
+	Duration      time.Duration
+	Target        string
+	Privileged    bool
+	MaxPacketLoss float64
+	Region        string
+	SlackURL      []string
+	LogLevel      string
+	Result        schema.Result
 }
 
-func (p *Ping) SetTimeout(i int) {
-	panic("implement me")
+// NewPing creates ping test
+func NewPing(region string) Shooter {
+	return &Ping{
+		Name:          fmt.Sprintf("Request from %s", region),
+		Rate:          constants.DefaultPingCount,
+		Interval:      constants.DefaultPingInterval,
+		Duration:      constants.DefaultWorkerDuration,
+		MaxPacketLoss: constants.DefaultMaxPacketLoss,
+		Region:        region,
+		Target:        constants.EmptyString,
+	}
 }
 
-func (p *Ping) SetLogLevel(s string) {
-	panic("implement me")
+// SetSlackURL set slack URL for notification
+func (p *Ping) SetSlackURL(s []string) {
+	p.SlackURL = s
 }
 
-func (p *Ping) RunWithResult() (*schema.Result, error) {
-	panic("implement me")
+// SetRate sets the number of echo requests
+func (p *Ping) SetRate(freq int) {
+	if freq > 0 {
+		p.Rate = freq
+	}
 }
 
-func (p *Ping) SetMethod(s string) {
-	panic("implement me")
+// SetTimeout sets timeout of whole ping test
+func (p *Ping) SetTimeout(i int) {
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	p.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
 }
 
-func (p *Ping) SetBody(m map[string]string) {
-	panic("implement me")
+// SetLogLevel sets loglevel
+func (p *Ping) SetLogLevel(logLevel string) {
+	p.LogLevel = logLevel
 }
 
-func (p *Ping) SetHeader(m map[string]string) {
-	panic("implement me")
+// SetTarget sets target host for ping
+func (p *Ping) SetTarget(url, port string) {
+	p.Target = parseHost(url)
+	logrus.Infof("Target: %s, Protocol: ICMP", p.Target)
 }
 
-// NewPing creates ping test
-func NewPing(region string) Shooter {
-	return &Ping{
-		Name:     fmt.Sprintf("Request from %s", region),
-		Attacker: nil,
-		Duration: constants.DefaultWorkerDuration,
-		Target:   constants.EmptyString,
+// SetMethod is not used for ping
+func (p *Ping) SetMethod(s string) {}
+
+// SetBody is not used for ping
+func (p *Ping) SetBody(m map[string]string) {}
+
+// SetHeader is not used for ping
+func (p *Ping) SetHeader(m map[string]string) {}
+
+// SetOption sets ping specific options
+func (p *Ping) SetOption(option schema.PingOption) {
+	if option.Count != nil {
+		p.SetRate(*option.Count)
 	}
-}
 
-// SetSlackURL set slack URL for notification
-func (p *Ping) SetSlackURL(s []string) {
-	p.SlackURL = s
-}
+	if option.Interval != nil && *option.Interval > 0 {
+		p.Interval = time.Duration(*option.Interval) * time.Millisecond
+	}
 
-// SetRate sets rate for api call
-func (p *Ping) SetRate(freq int) {
-	p.Attacker.Count = freq
+	if option.Privileged != nil {
+		p.Privileged = *option.Privileged
+	}
+
+	if option.MaxPacketLoss != nil {
+		p.MaxPacketLoss = *option.MaxPacketLoss
+	}
 }
 
-// SetTarget sets target for api call
-func (p *Ping) SetTarget(url, port string) {
-	p.Target = url
-	parsed, err := parseURL(url)
+// Ping sends echo requests and collects statistics
+func (p *Ping) Ping() error {
+	pinger, err := ping.NewPinger(p.Target)
 	if err != nil {
-		logrus.Errorf(err.Error())
+		return err
 	}
-	p.Attacker = ping.New(parsed)
+
+	pinger.Count = p.Rate
+	pinger.Interval = p.Interval
+	if p.Timeout > 0 {
+		pinger.Timeout = p.Timeout
+	}
+	pinger.SetPrivileged(p.Privileged)
+
+	if err := pinger.Run(); err != nil {
+		if !p.Privileged && os.IsPermission(err) {
+			return fmt.Errorf("%s: unprivileged ping requires net.ipv4.ping_group_range to include the current group", err.Error())
+		}
+		return err
+	}
+
+	p.SetResult(pinger.Statistics())
+
+	return nil
 }
 
 // Run starts ping test
 func (p *Ping) Run() error {
-	p.Attacker.SetPrivileged(true)
+	if err := p.Ping(); err != nil {
+		if sendErr := SendErrorAlarm(p.SlackURL, p.Target, p.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&p.Result)
+
+	if p.LogLevel == "debug" {
+		if err := p.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(p.SlackURL) > 0 && p.Result.Failed() {
+		if err := p.SendAlarm(); err != nil {
+			return err
+		}
+	}
 
-	if err := p.Attacker.Run(); err != nil {
+	if err := p.SaveData(); err != nil {
 		return err
 	}
 
-	stats := p.Attacker.Statistics()
+	return nil
+}
+
+// RunWithResult runs ping test and returns result
+func (p *Ping) RunWithResult() (*schema.Result, error) {
+	if err := p.Ping(); err != nil {
+		return nil, err
+	}
+	RedactResult(&p.Result)
+
+	return &p.Result, nil
+}
+
+// SetResult sets the statistics to Ping.Result
+func (p *Ping) SetResult(stats *ping.Statistics) {
+	ps := schema.PingStatistics{
+		Addr:          stats.Addr,
+		Privileged:    p.Privileged,
+		PacketsSent:   stats.PacketsSent,
+		PacketsRecv:   stats.PacketsRecv,
+		PacketLoss:    stats.PacketLoss,
+		MaxPacketLoss: p.MaxPacketLoss,
+		MinRtt:        stats.MinRtt,
+		AvgRtt:        stats.AvgRtt,
+		MaxRtt:        stats.MaxRtt,
+		StdDevRtt:     stats.StdDevRtt,
+	}
+
+	if stats.IPAddr != nil {
+		ps.IPAddr = stats.IPAddr.String()
+	}
+
+	p.Result = schema.Result{
+		Ping: &ps,
+	}
+}
+
+// PrintResult prints result
+func (p *Ping) PrintResult() error {
+	var scanData = struct {
+		Summary schema.PingStatistics
+	}{
+		Summary: *p.Result.Ping,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate": color.DecorateAttr,
+		"format":   tools.Formatting,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.PingTemplate))
+
+	if err := tt.Execute(w, scanData); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
 
-	logrus.Infof("%+v", stats)
+	stats := p.Result.Ping
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Sent", "Received", "Packet Loss", "Min RTT", "Avg RTT", "Max RTT", "StdDev RTT"})
+	table.Append([]string{
+		tools.IntToString(stats.PacketsSent),
+		tools.IntToString(stats.PacketsRecv),
+		fmt.Sprintf("%.1f%%", stats.PacketLoss),
+		stats.MinRtt.String(),
+		stats.AvgRtt.String(),
+		stats.MaxRtt.String(),
+		stats.StdDevRtt.String(),
+	})
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
 
 	return nil
 }
 
-// parseURL retrieves host from url
-func parseURL(ori string) (string, error) {
-	u, err := url.Parse(ori)
-	if err != nil {
-		return ori, err
+// SendAlarm sends slack alarm
+func (p *Ping) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	stats := p.Result.Ping
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", p.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Host*: `%s`", stats.Addr),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Ping IP*: `%s`", stats.IPAddr),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Packet Loss*: %.1f%% (%d/%d received)", stats.PacketLoss, stats.PacketsRecv, stats.PacketsSent),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	fields := []slacker.Field{
+		{
+			Title: "Min RTT",
+			Value: stats.MinRtt.String(),
+			Short: true,
+		},
+		{
+			Title: "Avg RTT",
+			Value: stats.AvgRtt.String(),
+			Short: true,
+		},
+		{
+			Title: "Max RTT",
+			Value: stats.MaxRtt.String(),
+			Short: true,
+		},
+		{
+			Title: "StdDev RTT",
+			Value: stats.StdDevRtt.String(),
+			Short: true,
+		},
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color:  constants.ErrorColor,
+		Text:   "*Ping statistics*",
+		Fields: fields,
+	})
+
+	return sendMessage(p.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (p *Ping) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WritePingData("bigshot", "synthetics", p.Region, *p.Result.Ping); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseHost retrieves host from url
+func parseHost(ori string) string {
+	raw := ori
+	if !strings.Contains(raw, "://") {
+		raw = fmt.Sprintf("//%s", raw)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || len(u.Hostname()) == 0 {
+		return ori
 	}
 
-	return u.Host, nil
+	return u.Hostname()
 }
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-ping/ping"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestPingSetResult(t *testing.T) {
+	p := NewPing("test").(*Ping)
+	p.SetOption(schema.PingOption{MaxPacketLoss: aws.Float64(25)})
+
+	p.SetResult(&ping.Statistics{
+		Addr:        "example.com",
+		IPAddr:      &net.IPAddr{IP: net.ParseIP("93.184.216.34")},
+		PacketsSent: 4,
+		PacketsRecv: 3,
+		PacketLoss:  25,
+		MinRtt:      10 * time.Millisecond,
+		AvgRtt:      12 * time.Millisecond,
+		MaxRtt:      15 * time.Millisecond,
+		StdDevRtt:   2 * time.Millisecond,
+	})
+
+	expected := schema.PingStatistics{
+		Addr:          "example.com",
+		IPAddr:        "93.184.216.34",
+		PacketsSent:   4,
+		PacketsRecv:   3,
+		PacketLoss:    25,
+		MaxPacketLoss: 25,
+		MinRtt:        10 * time.Millisecond,
+		AvgRtt:        12 * time.Millisecond,
+		MaxRtt:        15 * time.Millisecond,
+		StdDevRtt:     2 * time.Millisecond,
+	}
+	if *p.Result.Ping != expected {
+		t.Errorf("expected: %+v, got: %+v", expected, *p.Result.Ping)
+	}
+
+	if p.Result.Failed() {
+		t.Error("packet loss within maximum should not fail")
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// NewProtocol returns the protocol negotiated for response
+func NewProtocol(response *http.Response) *schema.Protocol {
+	if response == nil {
+		return nil
+	}
+
+	protocol := schema.Protocol{
+		HTTPVersion: response.Proto,
+	}
+
+	if cs := response.TLS; cs != nil {
+		protocol.ALPN = cs.NegotiatedProtocol
+		protocol.TLSVersion = TLSVersionName(cs.Version)
+		protocol.CipherSuite = tls.CipherSuiteName(cs.CipherSuite)
+		protocol.SessionResumed = cs.DidResume
+		protocol.OCSPStapled = len(cs.OCSPResponse) > 0
+	}
+
+	return &protocol
+}
+
+// TLSVersionName returns the name of TLS version
+func TLSVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+
+	return fmt.Sprintf("0x%04X", version)
+}
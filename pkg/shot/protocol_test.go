@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestNewProtocol(t *testing.T) {
+	tcs := []struct {
+		name     string
+		response *http.Response
+		expected schema.Protocol
+	}{
+		{
+			name:     "plain http",
+			response: &http.Response{Proto: "HTTP/1.1"},
+			expected: schema.Protocol{HTTPVersion: "HTTP/1.1"},
+		},
+		{
+			name: "h2 with stapled OCSP",
+			response: &http.Response{
+				Proto: "HTTP/2.0",
+				TLS: &tls.ConnectionState{
+					Version:            tls.VersionTLS13,
+					CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+					NegotiatedProtocol: "h2",
+					DidResume:          true,
+					OCSPResponse:       []byte{0x30},
+				},
+			},
+			expected: schema.Protocol{
+				HTTPVersion:    "HTTP/2.0",
+				ALPN:           "h2",
+				TLSVersion:     "TLS 1.3",
+				CipherSuite:    "TLS_AES_128_GCM_SHA256",
+				SessionResumed: true,
+				OCSPStapled:    true,
+			},
+		},
+		{
+			name: "downgraded to HTTP/1.1 and TLS 1.2",
+			response: &http.Response{
+				Proto: "HTTP/1.1",
+				TLS: &tls.ConnectionState{
+					Version:     tls.VersionTLS12,
+					CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				},
+			},
+			expected: schema.Protocol{
+				HTTPVersion: "HTTP/1.1",
+				TLSVersion:  "TLS 1.2",
+				CipherSuite: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if protocol := NewProtocol(tc.response); *protocol != tc.expected {
+				t.Errorf("expected: %+v, got: %+v", tc.expected, *protocol)
+			}
+		})
+	}
+}
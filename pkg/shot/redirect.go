@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// credentialHeaders are not sent to other hosts when following redirects
+var credentialHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// FollowRedirects sends request and follows redirects up to max hops
+// Checks are evaluated on the final response with the total time of every hop.
+func (t *Tracer) FollowRedirects(spec RequestSpec) (schema.Result, error) {
+	maxHops := constants.DefaultMaxRedirects
+	if spec.Redirect.MaxHops != nil {
+		maxHops = *spec.Redirect.MaxHops
+	}
+
+	var chain schema.Result
+	hopSpec := spec
+	hopSpec.Redirect = nil
+
+	for i := 0; ; i++ {
+		result, err := t.send(hopSpec)
+		if err != nil {
+			return schema.Result{}, err
+		}
+
+		// scheme can be changed by redirects
+		td := Calculated(result.TracingData, true)
+		if i == 0 {
+			chain.TracingData.URL = td.URL
+		}
+		addTimings(&chain.TracingData, td)
+		chain.TracingData.ConnectAddr = td.ConnectAddr
+		if chain.Certificate == nil {
+			chain.Certificate = result.Certificate
+		}
+
+		location := http.Header(result.Response.Header).Get("Location")
+		chain.Redirects = append(chain.Redirects, schema.RedirectHop{
+			URL:         td.URL,
+			StatusCode:  result.Response.StatusCode,
+			Location:    location,
+			TracingData: td,
+		})
+
+		if result.Failure != nil && result.Response.StatusCode == 0 {
+			chain.Failure = result.Failure
+			return chain, nil
+		}
+
+		chain.Response = result.Response
+		if !IsRedirect(result.Response.StatusCode) || len(location) == 0 {
+			break
+		}
+
+		if i >= maxHops {
+			chain.Failure = &schema.Failure{
+				Phase:   constants.PhaseRedirect,
+				Reason:  constants.ReasonTooManyRedirects,
+				Message: fmt.Sprintf("stopped after %d redirects at %s", maxHops, td.URL),
+			}
+			return chain, nil
+		}
+
+		next, err := NextHop(hopSpec, td.URL, location, result.Response.StatusCode)
+		if err != nil {
+			chain.Failure = &schema.Failure{
+				Phase:   constants.PhaseRedirect,
+				Reason:  constants.ReasonRedirectError,
+				Message: err.Error(),
+			}
+			return chain, nil
+		}
+		hopSpec = next
+	}
+
+	final := chain.Redirects[len(chain.Redirects)-1].URL
+	chain.Assertions = checker.Assert(spec.Checks, chain.Response, chain.TracingData.Total)
+	chain.Assertions = append(chain.Assertions, checker.AssertRedirect(*spec.Redirect, chain.TracingData.URL, final)...)
+	chain.Failure = ClassifyAssertions(chain.Assertions)
+
+	return chain, nil
+}
+
+// IsRedirect returns whether status code is redirect which has location to follow
+func IsRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+
+	return false
+}
+
+// NextHop returns request of location which current request is redirected to
+// Method is changed to GET like browsers and credentials are not sent to other hosts.
+func NextHop(spec RequestSpec, current, location string, code int) (RequestSpec, error) {
+	base, err := url.Parse(current)
+	if err != nil {
+		return RequestSpec{}, err
+	}
+
+	ref, err := url.Parse(location)
+	if err != nil {
+		return RequestSpec{}, fmt.Errorf("location of redirect is not correct: %s", location)
+	}
+
+	next := base.ResolveReference(ref)
+	if next.Scheme != "http" && next.Scheme != "https" {
+		return RequestSpec{}, fmt.Errorf("scheme of redirect location is not supported: %s", location)
+	}
+
+	// query of the first request is already in the URL of hops
+	spec.URL = next.String()
+	spec.Query = nil
+
+	changeMethod := code == http.StatusSeeOther && spec.Method != http.MethodHead
+	changeMethod = changeMethod || ((code == http.StatusMovedPermanently || code == http.StatusFound) && spec.Method == http.MethodPost)
+
+	header := map[string]string{}
+	for k, v := range spec.Header {
+		if changeMethod && strings.EqualFold(k, "Content-Type") {
+			continue
+		}
+
+		if !strings.EqualFold(next.Hostname(), base.Hostname()) && isCredentialHeader(k) {
+			continue
+		}
+		header[k] = v
+	}
+	spec.Header = header
+
+	if changeMethod {
+		spec.Method = http.MethodGet
+		spec.Body = nil
+		spec.Payload = nil
+	}
+
+	return spec, nil
+}
+
+// isCredentialHeader returns whether header has credentials
+func isCredentialHeader(key string) bool {
+	for _, h := range credentialHeaders {
+		if strings.EqualFold(h, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DrawRedirectTable draws status and timings of each hop of redirects
+func DrawRedirectTable(hops []schema.RedirectHop) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Hop", "URL", "Status", "DNS Lookup", "TCP Connection", "TLS Handshake", "Server Processing", "Total"})
+	for i, hop := range hops {
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			hop.URL,
+			hopStatus(hop),
+			hop.TracingData.DNSLookup.String(),
+			hop.TracingData.TCPConnection.String(),
+			hop.TracingData.TLSHandShacking.String(),
+			hop.TracingData.ServerProcessing.String(),
+			hop.TracingData.Total.String(),
+		})
+	}
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// redirectBlock returns slack block of redirect hops
+func redirectBlock(hops []schema.RedirectHop) slacker.Block {
+	lines := []string{"*Redirects*"}
+	for i, hop := range hops {
+		lines = append(lines, fmt.Sprintf("%d. `%s` %s (%s)", i+1, hopStatus(hop), hop.URL, hop.TracingData.Total.String()))
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// hopStatus returns status code of hop or dash if request failed
+func hopStatus(hop schema.RedirectHop) string {
+	if hop.StatusCode == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d", hop.StatusCode)
+}
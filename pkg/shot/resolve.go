@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// SetResolveOption sets IPs which host of target is pinned to
+func (t *Tracer) SetResolveOption(option schema.ResolveOption) {
+	t.Resolve = &option
+}
+
+// FanOut returns whether every IP of host is probed with its own result
+func (t *Tracer) FanOut() bool {
+	return t.Resolve != nil && aws.BoolValue(t.Resolve.All)
+}
+
+// BackendIPs returns IPs of target to probe separately
+// Every A and AAAA record of host is looked up if all is set.
+func (t *Tracer) BackendIPs(ctx context.Context) ([]string, error) {
+	if t.Resolve == nil {
+		return nil, nil
+	}
+
+	if len(t.Resolve.IPs) > 0 {
+		return t.Resolve.IPs, nil
+	}
+
+	if !t.FanOut() {
+		return nil, nil
+	}
+
+	host, err := targetHost(t.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP.String())
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no A or AAAA record of host: %s", host)
+	}
+
+	return ips, nil
+}
+
+// dialContext returns dial function which connects to pinned IP instead of host of target
+// Hosts of other requests like redirects are resolved as usual.
+func (t *Tracer) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _ := targetHost(t.Target)
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		addrHost, port, err := net.SplitHostPort(addr)
+		if err == nil && len(t.pinnedIP) > 0 && strings.EqualFold(addrHost, host) {
+			addr = net.JoinHostPort(t.pinnedIP, port)
+		}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+// BackendsResult returns result of target from results of each backend IP
+// The first failed backend is used as result of target so that it is alarmed.
+func BackendsResult(backends []schema.BackendResult) schema.Result {
+	main := backends[0]
+	for _, backend := range backends {
+		if backend.Result.Failed() {
+			main = backend
+			break
+		}
+	}
+
+	result := main.Result
+	if result.Failure != nil {
+		failure := *result.Failure
+		failure.Message = fmt.Sprintf("backend %s: %s", main.IP, failure.Message)
+		result.Failure = &failure
+	}
+	result.Backends = backends
+
+	return result
+}
+
+// ResolveFailure returns failure of looking up backend IPs of target
+func ResolveFailure(err error) *schema.Failure {
+	return ClassifyError(err, schema.TracingData{DNSStart: time.Now()})
+}
+
+// DrawBackendTable draws a row of each backend IP
+func DrawBackendTable(backends []schema.BackendResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"IP", "Status", "TCP Connection", "TLS Handshake", "Server Processing", "Total", "Result"})
+	for _, backend := range backends {
+		table.Append([]string{
+			backend.IP,
+			backendStatus(backend.Result),
+			backend.Result.TracingData.TCPConnection.String(),
+			backend.Result.TracingData.TLSHandShacking.String(),
+			backend.Result.TracingData.ServerProcessing.String(),
+			backend.Result.TracingData.Total.String(),
+			backendResult(backend.Result),
+		})
+	}
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// backendBlock returns slack block which lists failed and passed backend IPs
+func backendBlock(backends []schema.BackendResult) slacker.Block {
+	var failed, passed []string
+	for _, backend := range backends {
+		if backend.Result.Failed() {
+			failed = append(failed, fmt.Sprintf("`%s` %s (%s)", backend.IP, backendResult(backend.Result), backend.Result.TracingData.Total.String()))
+		} else {
+			passed = append(passed, fmt.Sprintf("`%s` %s (%s)", backend.IP, backendStatus(backend.Result), backend.Result.TracingData.Total.String()))
+		}
+	}
+
+	lines := []string{fmt.Sprintf("*Failed Backends* (%d/%d)", len(failed), len(backends))}
+	lines = append(lines, failed...)
+	lines = append(lines, fmt.Sprintf("*Passed Backends* (%d/%d)", len(passed), len(backends)))
+	lines = append(lines, passed...)
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// backendStatus returns status code of backend or dash if request failed
+func backendStatus(result schema.Result) string {
+	if result.Response.StatusCode == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d", result.Response.StatusCode)
+}
+
+// backendResult returns PASS or reason of failure of backend
+func backendResult(result schema.Result) string {
+	if !result.Failed() {
+		return "PASS"
+	}
+
+	if result.Failure != nil {
+		return result.Failure.Reason
+	}
+
+	return "FAIL"
+}
+
+// targetHost returns host of target URL without port
+func targetHost(target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	return u.Hostname(), nil
+}
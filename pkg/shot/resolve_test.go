@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestDialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	tracer := &Tracer{Target: "http://api.example.invalid:" + port, pinnedIP: "127.0.0.1"}
+	dial := tracer.dialContext(&net.Dialer{})
+
+	conn, err := dial(context.Background(), "tcp", "api.example.invalid:"+port)
+	if err != nil {
+		t.Fatalf("pinned host should be connected to pinned IP: %s", err.Error())
+	}
+	conn.Close()
+
+	if _, err := dial(context.Background(), "tcp", "other.example.invalid:"+port); err == nil {
+		t.Error("host other than target should not be pinned")
+	}
+}
+
+func TestBackendsResult(t *testing.T) {
+	ok := schema.Result{Response: schema.Response{StatusCode: 200}}
+	refused := schema.Result{Failure: &schema.Failure{Phase: constants.PhaseTCP, Reason: constants.ReasonTCPRefused, Message: "connection refused"}}
+
+	result := BackendsResult([]schema.BackendResult{
+		{IP: "10.0.3.16", Result: ok},
+		{IP: "10.0.3.17", Result: refused},
+		{IP: "10.0.3.18", Result: ok},
+	})
+
+	if !result.Failed() || result.Failure.Message != "backend 10.0.3.17: connection refused" || len(result.Backends) != 3 {
+		t.Errorf("result of failed backend is expected: %+v", result.Failure)
+	}
+
+	if refused.Failure.Message != "connection refused" {
+		t.Error("failure of backend should not be changed")
+	}
+
+	block := backendBlock(result.Backends)
+	if !strings.Contains(block.Text.Text, "*Failed Backends* (1/3)\n`10.0.3.17` tcp_refused") || !strings.Contains(block.Text.Text, "`10.0.3.18` 200") {
+		t.Errorf("backend IPs are not listed correctly: %s", block.Text.Text)
+	}
+
+	result = BackendsResult([]schema.BackendResult{{IP: "10.0.3.16", Result: ok}, {IP: "10.0.3.18", Result: ok}})
+	if result.Failed() {
+		t.Error("result should pass when every backend passes")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// SetRetry sets retries of check and seconds to wait before the first retry
+func (t *Tracer) SetRetry(retries, backoff int) {
+	if backoff <= 0 {
+		backoff = constants.DefaultRetryBackoff
+	}
+
+	t.Retries = retries
+	t.RetryBackoff = backoff
+}
+
+// NewAttempt returns attempt of result
+func NewAttempt(number int, backoff time.Duration, result schema.Result) schema.Attempt {
+	return schema.Attempt{
+		Number:     number,
+		Passed:     !result.Failed(),
+		StatusCode: result.Response.StatusCode,
+		Failure:    result.Failure,
+		Total:      result.TracingData.Total,
+		Backoff:    backoff,
+	}
+}
+
+// DrawAttemptTable draws a row of each attempt
+func DrawAttemptTable(attempts []schema.Attempt) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Attempt", "Backoff", "Status", "Total", "Result"})
+	for _, attempt := range attempts {
+		table.Append([]string{
+			fmt.Sprintf("%d", attempt.Number),
+			attempt.Backoff.String(),
+			attemptStatus(attempt),
+			attempt.Total.String(),
+			attemptResult(attempt),
+		})
+	}
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// attemptBlock returns slack block of attempts
+func attemptBlock(attempts []schema.Attempt) slacker.Block {
+	lines := []string{"*Attempts*"}
+	for _, attempt := range attempts {
+		lines = append(lines, fmt.Sprintf("%d. `%s` %s (%s)", attempt.Number, attemptResult(attempt), attemptStatus(attempt), attempt.Total.String()))
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// attemptStatus returns status code of attempt or dash if request failed
+func attemptStatus(attempt schema.Attempt) string {
+	if attempt.StatusCode == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d", attempt.StatusCode)
+}
+
+// attemptResult returns PASS or reason of failure of attempt
+func attemptResult(attempt schema.Attempt) string {
+	if attempt.Passed {
+		return "PASS"
+	}
+
+	if attempt.Failure != nil {
+		return attempt.Failure.Reason
+	}
+
+	return "FAIL"
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestTracer returns tracer of GET request to url
+func newTestTracer(url string) *Tracer {
+	tracer := NewTracer("test").(*Tracer)
+	tracer.SetTarget(url, "")
+	tracer.SetMethod(http.MethodGet)
+	tracer.SetTimeout(2)
+
+	return tracer
+}
+
+// failingServer returns server which fails the first n requests with 503
+func failingServer(n int32) *httptest.Server {
+	var count int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestTraceRetries(t *testing.T) {
+	testData := []struct {
+		Name             string
+		Failures         int32
+		Retries          int
+		Failed           bool
+		Attempts         int
+		SucceededOnRetry bool
+	}{
+		{Name: "passes without retry", Failures: 0, Retries: 2, Failed: false, Attempts: 1, SucceededOnRetry: false},
+		{Name: "passes on retry", Failures: 1, Retries: 2, Failed: false, Attempts: 2, SucceededOnRetry: true},
+		{Name: "every attempt fails", Failures: 10, Retries: 1, Failed: true, Attempts: 2, SucceededOnRetry: false},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			server := failingServer(td.Failures)
+			defer server.Close()
+
+			tracer := newTestTracer(server.URL)
+			tracer.SetRetry(td.Retries, 1)
+			if err := tracer.Trace(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := tracer.Result
+			if result.Failed() != td.Failed {
+				t.Errorf("expected failed: %t, got: %t", td.Failed, result.Failed())
+			}
+
+			if len(result.Attempts) != td.Attempts {
+				t.Fatalf("expected %d attempts, got: %d", td.Attempts, len(result.Attempts))
+			}
+
+			for i, attempt := range result.Attempts {
+				passed := i == len(result.Attempts)-1 && !td.Failed
+				if attempt.Number != i+1 || attempt.Passed != passed {
+					t.Errorf("attempt %d - expected passed: %t, got: %+v", i+1, passed, attempt)
+				}
+			}
+
+			if result.SucceededOnRetry() != td.SucceededOnRetry {
+				t.Errorf("expected succeeded on retry: %t, got: %t", td.SucceededOnRetry, result.SucceededOnRetry())
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// SetSampleOption sets number of requests in a check and whether they share connection
+func (t *Tracer) SetSampleOption(option schema.SampleOption) {
+	if option.Count != nil {
+		t.SetRate(*option.Count)
+	}
+	t.SampleInterval = time.Duration(aws.IntValue(option.Interval)) * time.Millisecond
+	t.ReuseConnection = aws.BoolValue(option.ReuseConnection)
+}
+
+// SamplesResult returns result of target from results of samples
+// The first failed sample is used as result of target so that it is alarmed.
+func SamplesResult(samples []schema.Result) schema.Result {
+	index := len(samples) - 1
+	for i, sample := range samples {
+		if sample.Failed() {
+			index = i
+			break
+		}
+	}
+
+	result := samples[index]
+	if result.Failure != nil {
+		failure := *result.Failure
+		failure.Message = fmt.Sprintf("sample %d: %s", index+1, failure.Message)
+		result.Failure = &failure
+	}
+
+	statistics := NewSampleStatistics(samples)
+	result.Samples = &statistics
+
+	return result
+}
+
+// NewSampleStatistics returns statistics of timings of samples
+// Samples without response are only counted because their timings are partial.
+func NewSampleStatistics(samples []schema.Result) schema.SampleStatistics {
+	statistics := schema.SampleStatistics{
+		Count: len(samples),
+	}
+
+	var dns, tcp, tls, server, transfer, total []time.Duration
+	for _, sample := range samples {
+		if sample.Failed() {
+			statistics.Failed++
+		}
+
+		if sample.Response.StatusCode == 0 {
+			continue
+		}
+
+		dns = append(dns, sample.TracingData.DNSLookup)
+		tcp = append(tcp, sample.TracingData.TCPConnection)
+		tls = append(tls, sample.TracingData.TLSHandShacking)
+		server = append(server, sample.TracingData.ServerProcessing)
+		transfer = append(transfer, sample.TracingData.ContentTransfer)
+		total = append(total, sample.TracingData.Total)
+	}
+
+	statistics.DNSLookup = NewPhaseStatistics(dns)
+	statistics.TCPConnection = NewPhaseStatistics(tcp)
+	statistics.TLSHandShacking = NewPhaseStatistics(tls)
+	statistics.ServerProcessing = NewPhaseStatistics(server)
+	statistics.ContentTransfer = NewPhaseStatistics(transfer)
+	statistics.Total = NewPhaseStatistics(total)
+
+	return statistics
+}
+
+// NewPhaseStatistics returns min, median, 95th percentile and max of durations
+func NewPhaseStatistics(durations []time.Duration) schema.PhaseStatistics {
+	if len(durations) == 0 {
+		return schema.PhaseStatistics{}
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return schema.PhaseStatistics{
+		Min:    sorted[0],
+		Median: Percentile(sorted, 50),
+		P95:    Percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// Percentile returns the nearest-rank percentile of sorted durations
+func Percentile(sorted []time.Duration, percentile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// DrawSampleTable draws statistics of each phase
+func DrawSampleTable(statistics *schema.SampleStatistics, tls bool) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Phase", "Min", "Median", "P95", "Max"})
+
+	rows := []struct {
+		Name       string
+		Statistics schema.PhaseStatistics
+	}{
+		{Name: "DNS Lookup", Statistics: statistics.DNSLookup},
+		{Name: "TCP Connection", Statistics: statistics.TCPConnection},
+		{Name: "TLS Handshake", Statistics: statistics.TLSHandShacking},
+		{Name: "Server Processing", Statistics: statistics.ServerProcessing},
+		{Name: "Content Transfer", Statistics: statistics.ContentTransfer},
+		{Name: "Total", Statistics: statistics.Total},
+	}
+
+	for _, row := range rows {
+		if row.Name == "TLS Handshake" && !tls {
+			continue
+		}
+
+		table.Append([]string{
+			row.Name,
+			row.Statistics.Min.String(),
+			row.Statistics.Median.String(),
+			row.Statistics.P95.String(),
+			row.Statistics.Max.String(),
+		})
+	}
+	table.SetCaption(true, fmt.Sprintf("%d samples, %d failed", statistics.Count, statistics.Failed))
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// sampleBlock returns slack block of sample statistics
+func sampleBlock(statistics *schema.SampleStatistics) slacker.Block {
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Samples*: %d (%d failed)\nTotal min %s / median %s / p95 %s / max %s",
+				statistics.Count,
+				statistics.Failed,
+				statistics.Total.Min.String(),
+				statistics.Total.Median.String(),
+				statistics.Total.P95.String(),
+				statistics.Total.Max.String(),
+			),
+		},
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestNewPhaseStatistics(t *testing.T) {
+	var durations []time.Duration
+	for i := 20; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	statistics := NewPhaseStatistics(durations)
+	expected := schema.PhaseStatistics{
+		Min:    1 * time.Millisecond,
+		Median: 10 * time.Millisecond,
+		P95:    19 * time.Millisecond,
+		Max:    20 * time.Millisecond,
+	}
+	if statistics != expected {
+		t.Errorf("expected: %+v, got: %+v", expected, statistics)
+	}
+
+	if durations[0] != 20*time.Millisecond {
+		t.Error("durations should not be sorted in place")
+	}
+
+	if single := NewPhaseStatistics([]time.Duration{time.Second}); single.Median != time.Second || single.P95 != time.Second {
+		t.Errorf("statistics of single sample should be the sample: %+v", single)
+	}
+}
+
+func TestSamplesResult(t *testing.T) {
+	ok := func(total time.Duration) schema.Result {
+		return schema.Result{
+			Response:    schema.Response{StatusCode: 200},
+			TracingData: schema.TracingData{Total: total},
+		}
+	}
+	refused := schema.Result{Failure: &schema.Failure{Message: "connection refused"}}
+
+	result := SamplesResult([]schema.Result{ok(10 * time.Millisecond), refused, ok(30 * time.Millisecond)})
+	if !result.Failed() || result.Failure.Message != "sample 2: connection refused" {
+		t.Errorf("failed sample should be result: %+v", result.Failure)
+	}
+
+	if result.Samples.Count != 3 || result.Samples.Failed != 1 || result.Samples.Total.Max != 30*time.Millisecond || result.Samples.Total.Min != 10*time.Millisecond {
+		t.Errorf("samples without response should only be counted: %+v", result.Samples)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/secret"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// SecretResolver looks up secret references of targets
+// It can be replaced with secret.MemoryStore in tests.
+var SecretResolver secret.Resolver = secret.DefaultResolver{}
+
+// ResolveSecrets returns copy of target whose secret references are resolved
+func ResolveSecrets(target schema.Target, timeout time.Duration) (schema.Target, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return secret.ResolveTarget(ctx, SecretResolver, target)
+}
+
+// RedactResult replaces resolved secret values in result with their references
+func RedactResult(result *schema.Result) {
+	result.TracingData.URL = secret.Redact(result.TracingData.URL)
+	for k, values := range result.Response.Header {
+		for i, v := range values {
+			values[i] = secret.Redact(v)
+		}
+		result.Response.Header[k] = values
+	}
+
+	if result.Failure != nil {
+		result.Failure.Message = secret.Redact(result.Failure.Message)
+	}
+
+	for i := range result.Assertions {
+		result.Assertions[i].Expected = secret.Redact(result.Assertions[i].Expected)
+		result.Assertions[i].Actual = secret.Redact(result.Assertions[i].Actual)
+	}
+
+	for i := range result.Steps {
+		result.Steps[i].URL = secret.Redact(result.Steps[i].URL)
+		RedactResult(&result.Steps[i].Result)
+	}
+
+	if result.Warm != nil {
+		RedactResult(result.Warm)
+	}
+
+	for i := range result.Attempts {
+		if failure := result.Attempts[i].Failure; failure != nil {
+			failure.Message = secret.Redact(failure.Message)
+		}
+	}
+
+	for i := range result.Backends {
+		RedactResult(&result.Backends[i].Result)
+	}
+
+	for i := range result.Redirects {
+		result.Redirects[i].URL = secret.Redact(result.Redirects[i].URL)
+		result.Redirects[i].Location = secret.Redact(result.Redirects[i].Location)
+		result.Redirects[i].TracingData.URL = secret.Redact(result.Redirects[i].TracingData.URL)
+	}
+
+	if result.TCP != nil {
+		result.TCP.Response = secret.Redact(result.TCP.Response)
+	}
+
+	if result.WebSocket != nil {
+		result.WebSocket.URL = secret.Redact(result.WebSocket.URL)
+		result.WebSocket.Reply = secret.Redact(result.WebSocket.Reply)
+	}
+
+	if result.Load != nil {
+		result.Load.URL = secret.Redact(result.Load.URL)
+		errs := map[string]int{}
+		for msg, count := range result.Load.Errors {
+			errs[secret.Redact(msg)] += count
+		}
+		result.Load.Errors = errs
+	}
+}
+
+// redactMessage replaces resolved secret values in slack message
+func redactMessage(attachments []slacker.Attachment, blocks []slacker.Block) {
+	for i := range attachments {
+		attachments[i].Text = secret.Redact(attachments[i].Text)
+		for j := range attachments[i].Fields {
+			attachments[i].Fields[j].Title = secret.Redact(attachments[i].Fields[j].Title)
+			attachments[i].Fields[j].Value = secret.Redact(attachments[i].Fields[j].Value)
+		}
+	}
+
+	for i := range blocks {
+		if blocks[i].Text != nil {
+			blocks[i].Text.Text = secret.Redact(blocks[i].Text.Text)
+		}
+	}
+}
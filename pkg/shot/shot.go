@@ -18,10 +18,19 @@ package shot
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/olekukonko/tablewriter"
 
 	"github.com/DevopsArtFactory/bigshot/pkg/builder"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
 )
 
 type Shooter interface {
@@ -35,42 +44,191 @@ type Shooter interface {
 	SetSlackURL([]string)
 	Run() error
 	RunWithResult() (*schema.Result, error)
+	PrintResult() error
 }
 
 // NewShooter returns new shooter
 func NewShooter(t, region string) Shooter {
 	switch t {
-	case "trace":
+	case "trace", constants.HTTPType:
 		return NewTracer(region)
-	case "ping":
+	case constants.PingType:
 		return NewPing(region)
-	case "vegeta":
+	case "vegeta", constants.LoadType:
 		return NewVegeta(region)
+	case constants.DNSType:
+		return NewDNS(region)
+	case constants.TCPType:
+		return NewTCP(region)
+	case constants.GRPCType:
+		return NewGRPC(region)
+	case constants.WebSocketType:
+		return NewWebSocket(region)
 	}
 
 	return nil
 }
 
-// Shoot tries shooting target checking
-func Shoot(target schema.Target, resultNeeded bool) (*schema.Result, error) {
-	defaultRegion, err := builder.GetDefaultRegion(constants.DefaultProfile)
+// NewShooterWithTarget returns new shooter configured with target
+func NewShooterWithTarget(target schema.Target, region string) (Shooter, error) {
+	shooterType := constants.DefaultShooter
+	if target.Type != nil && len(*target.Type) > 0 {
+		shooterType = *target.Type
+	}
+
+	if target.URL == nil {
+		return nil, errors.New("url of target is required")
+	}
+
+	switch {
+	case IsHostOnlyType(shooterType):
+	case shooterType == constants.TCPType || shooterType == constants.GRPCType:
+		if len(aws.StringValue(target.Port)) == 0 {
+			return nil, errors.New("port of target is required")
+		}
+	default:
+		// port is optional when URL has scheme
+		if _, err := tools.ParseTargetURL(*target.URL, aws.StringValue(target.Port)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !IsHostOnlyType(shooterType) && !IsPortOnlyType(shooterType) && target.Method == nil && len(target.Steps) == 0 {
+		return nil, errors.New("method of target is required")
+	}
+
+	timeout := constants.DefaultTargetTimeout
+	if target.Timeout != nil {
+		timeout = *target.Timeout
+	}
+
+	// secrets are resolved right before checking and never written back to template
+	target, err := ResolveSecrets(target, time.Duration(timeout)*time.Second)
 	if err != nil {
 		return nil, err
 	}
 
-	shooter := NewShooter(constants.DefaultShooter, defaultRegion)
+	shooter := NewShooter(shooterType, region)
 	if shooter == nil {
-		return nil, errors.New("cannot create new finder")
+		return nil, fmt.Errorf("cannot find the right shooter for type: %s", shooterType)
 	}
-	shooter.SetTarget(*target.URL, *target.Port)
-	shooter.SetMethod(*target.Method)
+	shooter.SetTarget(*target.URL, aws.StringValue(target.Port))
+	shooter.SetMethod(aws.StringValue(target.Method))
 	if target.Body != nil {
 		shooter.SetBody(target.Body)
 	}
 	if target.Header != nil {
 		shooter.SetHeader(target.Header)
 	}
-	shooter.SetRate(1)
+
+	shooter.SetTimeout(timeout)
+
+	switch s := shooter.(type) {
+	case *Tracer:
+		s.SetRate(1)
+		s.SetChecks(target.Checks)
+		s.SetSteps(target.Steps)
+		s.SetQuery(target.Query)
+		s.SetPayload(target.Payload)
+		if target.Certificate != nil {
+			s.SetCertificateOption(*target.Certificate)
+		}
+		if target.FollowRedirects != nil {
+			s.SetRedirectOption(*target.FollowRedirects)
+		}
+		if target.Auth != nil {
+			s.SetAuth(*target.Auth)
+		}
+		if target.SigV4 != nil {
+			s.SetSigV4Option(*target.SigV4)
+		}
+		if target.TLS != nil {
+			s.SetTLSOption(*target.TLS)
+		}
+		if target.Resolve != nil {
+			s.SetResolveOption(*target.Resolve)
+		}
+		if target.Retries != nil {
+			s.SetRetry(*target.Retries, aws.IntValue(target.RetryBackoff))
+		}
+		if target.Samples != nil {
+			s.SetSampleOption(*target.Samples)
+		}
+		if target.Warm != nil {
+			s.SetWarm(*target.Warm)
+		}
+	case *Ping:
+		if target.Ping != nil {
+			s.SetOption(*target.Ping)
+		}
+	case *Vegeta:
+		s.SetQuery(target.Query)
+		s.SetPayload(target.Payload)
+		if target.Load != nil {
+			s.SetOption(*target.Load)
+		}
+		if target.Auth != nil {
+			s.SetAuth(*target.Auth)
+		}
+		if target.TLS != nil {
+			s.SetTLSOption(*target.TLS)
+		}
+	case *DNS:
+		if target.DNS != nil {
+			s.SetOption(*target.DNS)
+		}
+	case *TCP:
+		if target.TCP != nil {
+			s.SetOption(*target.TCP)
+		}
+	case *GRPC:
+		// explicit tls of grpc option is applied after TLS option enables it
+		if target.TLS != nil {
+			s.SetTLSOption(*target.TLS)
+		}
+		if target.GRPC != nil {
+			s.SetOption(*target.GRPC)
+		}
+	case *WebSocket:
+		if target.WebSocket != nil {
+			s.SetOption(*target.WebSocket)
+		}
+		if target.Auth != nil {
+			s.SetAuth(*target.Auth)
+		}
+		if target.TLS != nil {
+			s.SetTLSOption(*target.TLS)
+		}
+		if target.Certificate != nil {
+			s.SetCertificateOption(*target.Certificate)
+		}
+	}
+
+	return shooter, nil
+}
+
+// IsHostOnlyType returns whether the type of target only needs host of URL
+func IsHostOnlyType(t string) bool {
+	return t == constants.PingType || t == constants.DNSType
+}
+
+// IsPortOnlyType returns whether the type of target needs host and port without method
+func IsPortOnlyType(t string) bool {
+	return t == constants.TCPType || t == constants.GRPCType || t == constants.WebSocketType
+}
+
+// Shoot tries shooting target checking
+func Shoot(target schema.Target, resultNeeded bool) (*schema.Result, error) {
+	defaultRegion, err := builder.GetDefaultRegion(constants.DefaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	shooter, err := NewShooterWithTarget(target, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
 	if resultNeeded {
 		result, err := shooter.RunWithResult()
 		if err != nil {
@@ -81,3 +239,114 @@ func Shoot(target schema.Target, resultNeeded bool) (*schema.Result, error) {
 
 	return nil, shooter.Run()
 }
+
+// DrawTimingTable draws a single row table of durations
+func DrawTimingTable(header []string, durations []time.Duration) {
+	row := make([]string, len(durations))
+	for i, d := range durations {
+		row[i] = d.String()
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.Append(row)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// DrawAssertionTable draws result table of assertions
+func DrawAssertionTable(assertions []schema.Assertion) {
+	if len(assertions) == 0 {
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Expected", "Actual", "Result"})
+	table.SetAutoWrapText(false)
+	for _, assertion := range assertions {
+		table.Append([]string{assertion.Name, assertion.Expected, assertion.Actual, assertionResult(assertion)})
+	}
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// assertionBlock returns slack block of assertions
+func assertionBlock(assertions []schema.Assertion) slacker.Block {
+	lines := []string{"*Checks*"}
+	for _, assertion := range assertions {
+		lines = append(lines, fmt.Sprintf("`%s` %s: expected `%s`, actual `%s`", assertionResult(assertion), assertion.Name, assertion.Expected, assertion.Actual))
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// assertionResult returns PASS or FAIL of assertion
+func assertionResult(assertion schema.Assertion) string {
+	if assertion.Passed {
+		return "PASS"
+	}
+
+	return "FAIL"
+}
+
+// sendMessage sends slack message to all slack URLs
+// Resolved secret values are redacted from message.
+func sendMessage(slackURLs []string, attachments []slacker.Attachment, blocks []slacker.Block) error {
+	redactMessage(attachments, blocks)
+
+	slack := slacker.NewSlackClient()
+	for _, URL := range slackURLs {
+		if err := slack.SendMessageWithWebHook(attachments, blocks, URL); err != nil {
+			fmt.Println(err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendErrorAlarm sends error alarm
+func SendErrorAlarm(slackURLs []string, target, region, errorMsg string) error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("Error occurred: `%s`", target),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", region),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", errorMsg),
+		},
+	})
+
+	return sendMessage(slackURLs, attachments, blocks)
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/secret"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// SetSteps sets steps of transaction
+func (t *Tracer) SetSteps(steps []schema.Step) {
+	t.Steps = steps
+}
+
+// TraceSteps requests steps in order as one transaction with header of target
+// It stops at the first failed step because later steps may depend on it.
+func (t *Tracer) TraceSteps(header map[string]string) error {
+	variables := map[string]string{}
+	transaction := schema.Result{
+		TracingData: schema.TracingData{
+			URL: t.Target,
+		},
+	}
+
+	for i, step := range t.Steps {
+		name := stepName(step, i)
+		method := aws.StringValue(step.Method)
+		if len(method) == 0 {
+			method = constants.DefaultStepMethod
+		}
+		target, err := StepURL(t.Target, checker.ExpandVariables(aws.StringValue(step.Path), variables))
+		if err != nil {
+			return err
+		}
+
+		headers := map[string]string{}
+		for k, v := range header {
+			headers[k] = v
+		}
+		for k, v := range step.Header {
+			headers[k] = v
+		}
+
+		result, err := t.Request(RequestSpec{
+			Method:  method,
+			URL:     target,
+			Query:   expandMap(step.Query, variables),
+			Body:    expandMap(step.Body, variables),
+			Payload: expandPayload(step.Payload, variables),
+			Header:  expandMap(headers, variables),
+			Checks:  step.Checks,
+
+			// assertions on redirects are only for target without steps
+			Redirect: stepRedirect(t.Redirect),
+		})
+		if err != nil {
+			return err
+		}
+
+		transaction.Steps = append(transaction.Steps, schema.StepResult{
+			Name:   name,
+			Method: method,
+			URL:    target,
+			Result: result,
+		})
+		addTimings(&transaction.TracingData, result.TracingData)
+		transaction.TracingData.ConnectAddr = result.TracingData.ConnectAddr
+		transaction.Response = result.Response
+		if transaction.Certificate == nil {
+			transaction.Certificate = result.Certificate
+		}
+
+		for _, assertion := range result.Assertions {
+			assertion.Name = fmt.Sprintf("%s: %s", name, assertion.Name)
+			transaction.Assertions = append(transaction.Assertions, assertion)
+		}
+
+		if result.Failure != nil {
+			failure := *result.Failure
+			failure.Message = fmt.Sprintf("step %s: %s", name, failure.Message)
+			transaction.Failure = &failure
+			break
+		}
+
+		if transaction.Failure = extractVariables(name, step.Extract, result.Response, variables); transaction.Failure != nil {
+			break
+		}
+	}
+
+	t.Result = transaction
+
+	return nil
+}
+
+// StepURL returns URL of step whose path is appended to path of target
+// Query of path is merged with query of target.
+func StepURL(base, path string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	ref, err := url.Parse(path)
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	if len(ref.Path) > 0 {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+	}
+
+	if len(ref.RawQuery) > 0 {
+		query := u.Query()
+		for k, v := range ref.Query() {
+			query[k] = v
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// extractVariables extracts values from response into variables
+func extractVariables(step string, extractions []schema.Extraction, response schema.Response, variables map[string]string) *schema.Failure {
+	for _, extraction := range extractions {
+		value, err := checker.Extract(extraction, response)
+		if err != nil {
+			return &schema.Failure{
+				Phase:   constants.PhaseExtract,
+				Reason:  constants.ReasonExtractFailed,
+				Message: fmt.Sprintf("step %s: cannot extract %s: %s", step, aws.StringValue(extraction.Name), err.Error()),
+			}
+		}
+
+		// extracted values like tokens are redacted from results as secrets are
+		name := aws.StringValue(extraction.Name)
+		secret.Register(value, fmt.Sprintf("{{ %s }}", name))
+		variables[name] = value
+	}
+
+	return nil
+}
+
+// stepRedirect returns redirect option of steps which only has max hops
+func stepRedirect(option *schema.RedirectOption) *schema.RedirectOption {
+	if option == nil {
+		return nil
+	}
+
+	return &schema.RedirectOption{
+		MaxHops: option.MaxHops,
+	}
+}
+
+// stepName returns name of step or its position
+func stepName(step schema.Step, i int) string {
+	if step.Name != nil && len(*step.Name) > 0 {
+		return *step.Name
+	}
+
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// expandMap replaces variable references in keys and values of m
+func expandMap(m map[string]string, variables map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	expanded := map[string]string{}
+	for k, v := range m {
+		expanded[checker.ExpandVariables(k, variables)] = checker.ExpandVariables(v, variables)
+	}
+
+	return expanded
+}
+
+// expandPayload returns copy of payload whose variable references are replaced
+func expandPayload(payload *schema.Payload, variables map[string]string) *schema.Payload {
+	if payload == nil {
+		return nil
+	}
+
+	expanded := *payload
+	if payload.Raw != nil {
+		expanded.Raw = aws.String(checker.ExpandVariables(*payload.Raw, variables))
+	}
+
+	if payload.JSON != nil {
+		expanded.JSON = expandJSON(tools.NormalizeYAML(payload.JSON), variables)
+	}
+
+	expanded.Form = expandMap(payload.Form, variables)
+
+	if payload.Multipart != nil {
+		expanded.Multipart = make([]schema.MultipartField, len(payload.Multipart))
+		for i, field := range payload.Multipart {
+			if field.Value != nil {
+				field.Value = aws.String(checker.ExpandVariables(*field.Value, variables))
+			}
+			expanded.Multipart[i] = field
+		}
+	}
+
+	return &expanded
+}
+
+// expandJSON replaces variable references in strings of JSON value
+func expandJSON(v interface{}, variables map[string]string) interface{} {
+	switch value := v.(type) {
+	case string:
+		return checker.ExpandVariables(value, variables)
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range value {
+			m[k] = expandJSON(v, variables)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(value))
+		for i, v := range value {
+			l[i] = expandJSON(v, variables)
+		}
+		return l
+	}
+
+	return v
+}
+
+// addTimings adds durations of each phase of step to total
+func addTimings(total *schema.TracingData, td schema.TracingData) {
+	total.DNSLookup += td.DNSLookup
+	total.TCPConnection += td.TCPConnection
+	total.TLSHandShacking += td.TLSHandShacking
+	total.ServerProcessing += td.ServerProcessing
+	total.ContentTransfer += td.ContentTransfer
+	total.Total += td.Total
+}
+
+// DrawStepTable draws timings and status of each step with total of transaction
+func DrawStepTable(steps []schema.StepResult, total schema.TracingData, https bool) {
+	header := []string{"Step", "Status", "DNS Lookup", "TCP Connection"}
+	if https {
+		header = append(header, "TLS Handshake")
+	}
+	header = append(header, "Server Processing", "Content Transfer", "Total", "Result")
+
+	row := func(name, status string, td schema.TracingData, result string) []string {
+		r := []string{name, status, td.DNSLookup.String(), td.TCPConnection.String()}
+		if https {
+			r = append(r, td.TLSHandShacking.String())
+		}
+		return append(r, td.ServerProcessing.String(), td.ContentTransfer.String(), td.Total.String(), result)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	for _, step := range steps {
+		table.Append(row(fmt.Sprintf("%s %s", step.Method, step.Name), stepStatus(step), step.Result.TracingData, stepResult(step)))
+	}
+	table.Append(row("Transaction", constants.EmptyString, total, constants.EmptyString))
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// stepBlock returns slack block of steps
+func stepBlock(steps []schema.StepResult) slacker.Block {
+	lines := []string{"*Steps*"}
+	for _, step := range steps {
+		lines = append(lines, fmt.Sprintf("`%s` %s %s: %s (%s)", stepResult(step), step.Method, step.Name, stepStatus(step), step.Result.TracingData.Total.String()))
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: strings.Join(lines, "\n"),
+		},
+	}
+}
+
+// stepStatus returns status code of step or reason of failure
+func stepStatus(step schema.StepResult) string {
+	if step.Result.Response.StatusCode > 0 {
+		return fmt.Sprintf("%d", step.Result.Response.StatusCode)
+	}
+
+	if step.Result.Failure != nil {
+		return step.Result.Failure.Reason
+	}
+
+	return constants.EmptyString
+}
+
+// stepResult returns PASS or FAIL of step
+func stepResult(step schema.StepResult) string {
+	if step.Result.Failed() {
+		return "FAIL"
+	}
+
+	return "PASS"
+}
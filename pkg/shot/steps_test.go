@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestStepURL(t *testing.T) {
+	testData := []struct {
+		Base   string
+		Path   string
+		Output string
+	}{
+		{Base: "https://api.example.com", Path: "/login", Output: "https://api.example.com/login"},
+		{Base: "https://api.example.com/v1/", Path: "orders/42", Output: "https://api.example.com/v1/orders/42"},
+		{Base: "http://[::1]:8080/v1", Path: "/orders?page=2", Output: "http://[::1]:8080/v1/orders?page=2"},
+		{Base: "https://api.example.com/v1?lang=en", Path: "/me", Output: "https://api.example.com/v1/me?lang=en"},
+		{Base: "https://api.example.com/v1", Path: "", Output: "https://api.example.com/v1"},
+	}
+
+	for _, test := range testData {
+		output, err := StepURL(test.Base, test.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output != test.Output {
+			t.Errorf("expected: %s, got: %s", test.Output, output)
+		}
+	}
+}
+
+func TestExtractVariablesRedacted(t *testing.T) {
+	response := schema.Response{Body: []byte(`{"token":"eyJ.step.token"}`)}
+	extractions := []schema.Extraction{{Name: aws.String("token"), JSONPath: aws.String("$.token")}}
+
+	variables := map[string]string{}
+	if failure := extractVariables("login", extractions, response, variables); failure != nil {
+		t.Fatal(failure.Message)
+	}
+
+	if variables["token"] != "eyJ.step.token" {
+		t.Errorf("token is not extracted: %v", variables)
+	}
+
+	result := schema.Result{Failure: &schema.Failure{Message: "GET https://api.example.com/me?token=eyJ.step.token: 401"}}
+	RedactResult(&result)
+	if result.Failure.Message != "GET https://api.example.com/me?token={{ token }}: 401" {
+		t.Errorf("extracted value is not redacted: %s", result.Failure.Message)
+	}
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// maxTCPResponseDisplay is the maximum length of response kept in result
+const maxTCPResponseDisplay = 256
+
+type TCP struct {
+	Name     string
+	Host     string
+	Port     string
+	Payload  string
+	Expected string
+	Timeout  time.Duration
+	Region   string
+	SlackURL []string
+	LogLevel string
+	Result   schema.Result
+}
+
+// NewTCP creates TCP connection test
+func NewTCP(region string) Shooter {
+	return &TCP{
+		Name:    fmt.Sprintf("Request from %s", region),
+		Timeout: time.Duration(constants.DefaultTargetTimeout) * time.Second,
+		Region:  region,
+	}
+}
+
+// SetSlackURL set slack URL for notification
+func (t *TCP) SetSlackURL(s []string) {
+	t.SlackURL = s
+}
+
+// SetRate is not used for TCP
+func (t *TCP) SetRate(freq int) {}
+
+// SetTimeout sets timeout of whole TCP test
+func (t *TCP) SetTimeout(i int) {
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	t.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
+}
+
+// SetLogLevel sets loglevel
+func (t *TCP) SetLogLevel(logLevel string) {
+	t.LogLevel = logLevel
+}
+
+// SetTarget sets target host and port
+func (t *TCP) SetTarget(url, port string) {
+	t.Host = parseHost(url)
+	t.Port = port
+	logrus.Infof("Target: %s, Protocol: TCP", t.Address())
+}
+
+// SetMethod is not used for TCP
+func (t *TCP) SetMethod(s string) {}
+
+// SetBody is not used for TCP
+func (t *TCP) SetBody(m map[string]string) {}
+
+// SetHeader is not used for TCP
+func (t *TCP) SetHeader(m map[string]string) {}
+
+// SetOption sets TCP specific options
+func (t *TCP) SetOption(option schema.TCPOption) {
+	if option.Payload != nil {
+		t.Payload = *option.Payload
+	}
+
+	if option.Expected != nil {
+		t.Expected = *option.Expected
+	}
+}
+
+// Address returns host and port of target
+func (t *TCP) Address() string {
+	return net.JoinHostPort(t.Host, t.Port)
+}
+
+// Connect connects to target, sends payload and reads response
+func (t *TCP) Connect() error {
+	var expected *regexp.Regexp
+	if len(t.Expected) > 0 {
+		re, err := regexp.Compile(t.Expected)
+		if err != nil {
+			return err
+		}
+		expected = re
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
+	defer cancel()
+
+	td := schema.TracingData{
+		URL: t.Address(),
+	}
+	result := schema.TCPResult{
+		Address: t.Address(),
+	}
+
+	conn, err := DialTCP(ctx, t.Host, t.Port, &td)
+	if err != nil {
+		t.SetFailure(td, result, err)
+		return nil
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Payload) > 0 {
+		n, err := conn.Write([]byte(t.Payload))
+		result.BytesSent = n
+		if err != nil {
+			t.SetFailure(td, result, err)
+			return nil
+		}
+	}
+
+	var received []byte
+	if expected != nil {
+		buf := make([]byte, 4096)
+		for !expected.Match(received) && len(received) < constants.MaxResponseBodySize {
+			n, err := conn.Read(buf)
+			if n > 0 && td.GetFirstResponseBtye.IsZero() {
+				td.GetFirstResponseBtye = time.Now()
+			}
+			received = append(received, buf[:n]...)
+
+			if err != nil {
+				if len(received) == 0 {
+					t.SetFailure(td, result, err)
+					return nil
+				}
+				break
+			}
+		}
+	}
+	td.FinishRequest = time.Now()
+
+	result.BytesReceived = len(received)
+	result.Response = string(received)
+	if len(result.Response) > maxTCPResponseDisplay {
+		result.Response = result.Response[:maxTCPResponseDisplay]
+	}
+
+	t.Result = schema.Result{
+		TracingData: Calculated(td, false),
+		TCP:         &result,
+	}
+
+	if expected != nil {
+		t.Result.Assertions = []schema.Assertion{checker.AssertRegex("response regex", t.Expected, received)}
+		t.Result.Failure = ClassifyAssertions(t.Result.Assertions)
+	}
+
+	return nil
+}
+
+// DialTCP resolves host and connects to it with recording timings to tracing data
+func DialTCP(ctx context.Context, host, port string, td *schema.TracingData) (net.Conn, error) {
+	ip := host
+	if net.ParseIP(host) == nil {
+		td.DNSStart = time.Now()
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		td.DNSDone = time.Now()
+		ip = addrs[0].IP.String()
+	}
+
+	td.ConnectionStart = time.Now()
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, port))
+	if err != nil {
+		return nil, err
+	}
+	td.ConnectionDone = time.Now()
+	td.GotConn = td.ConnectionDone
+	td.ConnectAddr = conn.RemoteAddr().String()
+
+	return conn, nil
+}
+
+// SetFailure sets the result of failed connection with timings up to the failure
+func (t *TCP) SetFailure(td schema.TracingData, result schema.TCPResult, err error) {
+	td.FinishRequest = time.Now()
+	failure := ClassifyError(err, td)
+	logrus.Errorf("connection failed in %s phase: %s", failure.Phase, failure.Reason)
+
+	t.Result = schema.Result{
+		TracingData: Calculated(td, false),
+		TCP:         &result,
+		Failure:     failure,
+	}
+}
+
+// Run starts TCP connection test
+func (t *TCP) Run() error {
+	if err := t.Connect(); err != nil {
+		if sendErr := SendErrorAlarm(t.SlackURL, t.Address(), t.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&t.Result)
+
+	if t.LogLevel == "debug" {
+		if err := t.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.SlackURL) > 0 && t.Result.Failed() {
+		if err := t.SendAlarm(); err != nil {
+			return err
+		}
+	}
+
+	if err := t.SaveData(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunWithResult runs TCP connection test and returns result
+func (t *TCP) RunWithResult() (*schema.Result, error) {
+	if err := t.Connect(); err != nil {
+		return nil, err
+	}
+	RedactResult(&t.Result)
+
+	return &t.Result, nil
+}
+
+// PrintResult prints result
+func (t *TCP) PrintResult() error {
+	var scanData = struct {
+		Summary schema.Result
+	}{
+		Summary: t.Result,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate": color.DecorateAttr,
+		"format":   tools.Formatting,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.TCPTemplate))
+
+	if err := tt.Execute(w, scanData); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	DrawTimingTable([]string{"DNS Lookup", "TCP Connection", "First Byte", "Total"}, []time.Duration{
+		t.Result.TracingData.DNSLookup,
+		t.Result.TracingData.TCPConnection,
+		t.Result.TracingData.ServerProcessing,
+		t.Result.TracingData.Total,
+	})
+	DrawAssertionTable(t.Result.Assertions)
+
+	return nil
+}
+
+// SendAlarm sends slack alarm
+func (t *TCP) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	td := t.Result.TracingData
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", t.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Address*: `%s`", t.Address()),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Connect IP*: `%s`", td.ConnectAddr),
+		},
+	})
+
+	if failure := t.Result.Failure; failure != nil {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Failure*: `%s` in %s phase\n%s", failure.Reason, failure.Phase, failure.Message),
+			},
+		})
+	}
+
+	if len(t.Result.Assertions) > 0 {
+		blocks = append(blocks, assertionBlock(t.Result.Assertions))
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color: constants.ErrorColor,
+		Text:  fmt.Sprintf("*TCP connection result* - Total Time: %s", td.Total.String()),
+		Fields: []slacker.Field{
+			{
+				Title: "DNS Lookup",
+				Value: td.DNSLookup.String(),
+				Short: true,
+			},
+			{
+				Title: "TCP Connection",
+				Value: td.TCPConnection.String(),
+				Short: true,
+			},
+			{
+				Title: "First Byte",
+				Value: td.ServerProcessing.String(),
+				Short: true,
+			},
+			{
+				Title: "Response",
+				Value: strings.TrimSpace(t.Result.TCP.Response),
+			},
+		},
+	})
+
+	return sendMessage(t.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (t *TCP) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WriteTCPData("bigshot", "synthetics", t.Region, t.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
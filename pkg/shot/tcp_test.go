@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+// newTCPServer returns address of server which handles each connection with handle
+func newTCPServer(t *testing.T, handle func(net.Conn)) (string, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port
+}
+
+// refusedPort returns port on which nothing listens
+func refusedPort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	return port
+}
+
+func TestTCPConnect(t *testing.T) {
+	echoHost, echoPort := newTCPServer(t, func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err == nil {
+			conn.Write([]byte("+PONG " + line))
+		}
+	})
+	silentHost, silentPort := newTCPServer(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+	})
+
+	testData := []struct {
+		Name     string
+		Host     string
+		Port     string
+		Payload  string
+		Expected string
+		Reason   string
+		Sent     int
+	}{
+		{Name: "connected without payload", Host: echoHost, Port: echoPort},
+		{Name: "payload with matched response", Host: echoHost, Port: echoPort, Payload: "PING\n", Expected: `^\+PONG`, Sent: 5},
+		{Name: "payload with mismatched response", Host: echoHost, Port: echoPort, Payload: "PING\n", Expected: `^-ERR`, Reason: constants.ReasonCheckFailed, Sent: 5},
+		{Name: "connection refused", Host: "127.0.0.1", Port: refusedPort(t), Reason: constants.ReasonTCPRefused},
+		{Name: "read timeout", Host: silentHost, Port: silentPort, Payload: "PING\n", Expected: `^\+PONG`, Reason: constants.ReasonTimeoutFirstByte, Sent: 5},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			tcp := NewTCP("test").(*TCP)
+			tcp.SetTarget(td.Host, td.Port)
+			tcp.Payload = td.Payload
+			tcp.Expected = td.Expected
+			tcp.Timeout = 200 * time.Millisecond
+
+			if err := tcp.Connect(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := tcp.Result
+			if len(td.Reason) == 0 {
+				if result.Failed() {
+					t.Fatalf("expected success, got: %+v", result.Failure)
+				}
+				if result.TracingData.TCPConnection <= 0 || result.TracingData.ConnectAddr != tcp.Address() {
+					t.Errorf("connection is not traced: %+v", result.TracingData)
+				}
+			} else if result.Failure == nil || result.Failure.Reason != td.Reason {
+				t.Fatalf("expected failure reason: %s, got: %+v", td.Reason, result.Failure)
+			}
+
+			if result.TCP.BytesSent != td.Sent {
+				t.Errorf("expected %d bytes sent, got: %d", td.Sent, result.TCP.BytesSent)
+			}
+		})
+	}
+}
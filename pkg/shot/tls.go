@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// NewTLSConfig returns TLS configuration of option
+// Secret references of PEM values should be resolved before.
+func NewTLSConfig(option *schema.TLSOption) (*tls.Config, error) {
+	config := &tls.Config{}
+	if option == nil {
+		return config, nil
+	}
+	config.InsecureSkipVerify = aws.BoolValue(option.InsecureSkipVerify)
+
+	if option.ClientCert != nil || option.ClientKey != nil {
+		if option.ClientCert == nil || option.ClientKey == nil {
+			return nil, errors.New("both client_cert and client_key are required")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(*option.ClientCert), []byte(*option.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("client certificate is not correct: %s", err.Error())
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if option.CA != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(*option.CA)) {
+			return nil, errors.New("CA bundle has no certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// TLSConfigFailure returns failure of loading TLS configuration
+func TLSConfigFailure(err error) *schema.Failure {
+	return &schema.Failure{
+		Phase:   constants.PhaseTLS,
+		Reason:  constants.ReasonTLSConfigError,
+		Message: err.Error(),
+	}
+}
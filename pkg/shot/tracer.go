@@ -18,10 +18,11 @@ package shot
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
-	"html/template"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptrace"
@@ -30,12 +31,15 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/http2"
 
+	"github.com/DevopsArtFactory/bigshot/pkg/auth"
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
 	"github.com/DevopsArtFactory/bigshot/pkg/client"
 	"github.com/DevopsArtFactory/bigshot/pkg/color"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
@@ -54,12 +58,41 @@ type Tracer struct {
 	Method   string
 	Body     map[string]string
 	Header   map[string]string
+	Checks   *schema.Checks
+	Steps    []schema.Step
+	Query    map[string]string
+	Payload  *schema.Payload
+	Redirect *schema.RedirectOption
+	Auth     *schema.AuthOption
+	SigV4    *schema.SigV4Option
+	TLS      *schema.TLSOption
+	Resolve  *schema.ResolveOption
 	Protocol string
 	Region   string
 	SlackURL []string
 	Result   schema.Result
 	LogLevel string
 	Timeout  int
+
+	// Retries is the number of retries and RetryBackoff is seconds to wait before the first retry
+	Retries      int
+	RetryBackoff int
+
+	// SampleInterval is the time between samples and ReuseConnection keeps connection between them
+	SampleInterval  time.Duration
+	ReuseConnection bool
+
+	// Warm sends the second request on connection of the first one
+	Warm bool
+
+	// signer signs requests during a trace if SigV4 is set
+	signer *auth.SigV4
+
+	// pinnedIP is the IP which host of target is connected to during a trace
+	pinnedIP string
+
+	// ExpiryThresholds are days before certificate expiry to send alarm
+	ExpiryThresholds []int
 }
 
 // SetRate sets rate of request
@@ -78,16 +111,26 @@ func (t *Tracer) SetTimeout(i int) {
 
 // SetTarget sets the target for the request
 func (t *Tracer) SetTarget(url, port string) {
-	if port == "443" {
-		t.Protocol = constants.HTTPS
-		t.Target = fmt.Sprintf("%s://%s", strings.ToLower(t.Protocol), url)
-	} else {
-		t.Protocol = constants.HTTP
-		t.Target = fmt.Sprintf("%s://%s:%s", strings.ToLower(t.Protocol), url, port)
-	}
+	t.Protocol, t.Target = GetTargetURL(url, port)
 	logrus.Infof("Target: %s, Protocol: %s", t.Target, t.Protocol)
 }
 
+// GetTargetURL returns protocol and URL of target
+// URL can be a full URL with scheme or the legacy form of host with port.
+func GetTargetURL(url, port string) (string, string) {
+	u, err := tools.ParseTargetURL(url, port)
+	if err != nil {
+		logrus.Errorf("URL of target is not correct: %s", err.Error())
+		return constants.HTTP, url
+	}
+
+	if tools.IsSecureScheme(u.Scheme) {
+		return constants.HTTPS, u.String()
+	}
+
+	return constants.HTTP, u.String()
+}
+
 // SetLogLevel sets loglevel
 func (t *Tracer) SetLogLevel(logLevel string) {
 	logrus.Infof("LogLevel: %s", logLevel)
@@ -95,52 +138,275 @@ func (t *Tracer) SetLogLevel(logLevel string) {
 }
 
 // Trace starts tracing
+// Check is retried with exponential backoff until it passes or retries run out.
 func (t *Tracer) Trace() error {
-	var bodyJSON string
-	var err error
-	if t.Body != nil {
-		b, err := json.Marshal(t.Body)
-		if err != nil {
+	var attempts []schema.Attempt
+	for number := 1; ; number++ {
+		var backoff time.Duration
+		if number > 1 {
+			backoff = tools.GetExponentialTime(t.RetryBackoff, number-2)
+			logrus.Warnf("attempt %d of %s failed, retrying in %s", number-1, t.Target, backoff.String())
+			time.Sleep(backoff)
+		}
+
+		if err := t.traceBackends(); err != nil {
 			return err
 		}
+		attempts = append(attempts, NewAttempt(number, backoff, t.Result))
 
-		bodyJSON = string(b)
+		if !t.Result.Failed() || number > t.Retries {
+			break
+		}
 	}
 
-	var req *http.Request
-	if len(bodyJSON) > 0 {
-		req, err = http.NewRequest(t.Method, t.Target, bytes.NewBuffer([]byte(bodyJSON)))
-		if err != nil {
+	if t.Retries > 0 {
+		t.Result.Attempts = attempts
+	}
+
+	return nil
+}
+
+// traceBackends traces target once
+// Each pinned or resolved backend IP is traced separately with its own result.
+func (t *Tracer) traceBackends() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.Attacker.Timeout)
+	ips, err := t.BackendIPs(ctx)
+	cancel()
+	if err != nil {
+		t.FailBeforeRequest(ResolveFailure(err))
+		return nil
+	}
+
+	// every pinned IP has its own backend result even if it is the only one
+	if len(ips) == 0 {
+		return t.trace()
+	}
+
+	var backends []schema.BackendResult
+	for _, ip := range ips {
+		t.pinnedIP = ip
+		if err := t.trace(); err != nil {
 			return err
 		}
-	} else {
-		req, err = http.NewRequest(t.Method, t.Target, nil)
+		backends = append(backends, schema.BackendResult{IP: ip, Result: t.Result})
+	}
+	t.Result = BackendsResult(backends)
+
+	return nil
+}
+
+// trace traces samples of target with a new transport
+// Connections are reused between samples only if it is enabled.
+func (t *Tracer) trace() error {
+	tlsConfig, err := NewTLSConfig(t.TLS)
+	if err != nil {
+		t.FailBeforeRequest(TLSConfigFailure(err))
+		return nil
+	}
+	// sessions are shared by transports of samples so that resumption can be recorded
+	tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+
+	if err := t.SetupTransport(tlsConfig); err != nil {
+		return err
+	}
+	defer t.Attacker.CloseIdleConnections()
+
+	header, err := AuthorizedHeader(t.Header, t.Auth, t.Attacker.Timeout)
+	if err != nil {
+		t.FailBeforeRequest(AuthFailure(err))
+		return nil
+	}
+
+	if err := t.SetupSigner(); err != nil {
+		t.FailBeforeRequest(AuthFailure(err))
+		return nil
+	}
+
+	var samples []schema.Result
+	for i := 0; i < t.Rate || i == 0; i++ {
+		if i > 0 {
+			time.Sleep(t.SampleInterval)
+			if !t.ReuseConnection {
+				if err := t.SetupTransport(tlsConfig); err != nil {
+					samples = append(samples, t.FailedResult(schema.TracingData{URL: t.Target}, err))
+					continue
+				}
+			}
+		}
+
+		// error of one sample is recorded so that other samples are kept
+		result, err := t.sample(header)
 		if err != nil {
-			return err
+			result = t.FailedResult(schema.TracingData{URL: t.Target}, err)
 		}
+		samples = append(samples, result)
 	}
 
-	if t.Header != nil {
-		header := http.Header{}
-		for k, v := range t.Header {
-			header.Set(k, v)
+	if len(samples) == 1 {
+		t.Result = samples[0]
+		return nil
+	}
+	t.Result = SamplesResult(samples)
+
+	return nil
+}
+
+// sample sends one request or steps of target
+func (t *Tracer) sample(header map[string]string) (schema.Result, error) {
+	if len(t.Steps) > 0 {
+		if err := t.TraceSteps(header); err != nil {
+			return schema.Result{}, err
 		}
-		req.Header = header
+		return t.Result, nil
+	}
+
+	spec := RequestSpec{
+		Method:   t.Method,
+		URL:      t.Target,
+		Query:    t.Query,
+		Body:     t.Body,
+		Payload:  t.Payload,
+		Header:   header,
+		Checks:   t.Checks,
+		Redirect: t.Redirect,
+	}
+
+	result, err := t.Request(spec)
+	if err != nil || !t.Warm || result.Response.StatusCode == 0 {
+		return result, err
+	}
+
+	// connection of the first request is kept alive in transport
+	// Error of warm request is recorded in it so that the result of the first request is kept.
+	warm, err := t.Request(spec)
+	if err != nil {
+		warm = t.FailedResult(schema.TracingData{URL: spec.URL}, err)
+	}
+	result.Warm = &warm
+
+	return result, nil
+}
+
+// RequestSpec is the specification of one request
+type RequestSpec struct {
+	Method  string
+	URL     string
+	Query   map[string]string
+	Body    map[string]string
+	Payload *schema.Payload
+	Header  map[string]string
+	Checks  *schema.Checks
+
+	// Redirect enables following redirects
+	Redirect *schema.RedirectOption
+}
+
+// Request sends request and returns the result with timings of each phase
+// Redirects are followed with every hop recorded if they are enabled.
+func (t *Tracer) Request(spec RequestSpec) (schema.Result, error) {
+	if spec.Redirect != nil {
+		return t.FollowRedirects(spec)
+	}
+
+	return t.send(spec)
+}
+
+// send sends one request without following redirects
+func (t *Tracer) send(spec RequestSpec) (schema.Result, error) {
+	target, err := WithQuery(spec.URL, spec.Query)
+	if err != nil {
+		return schema.Result{}, err
+	}
+
+	body, contentType, err := NewRequestBody(spec.Body, spec.Payload)
+	if err != nil {
+		return schema.Result{}, err
+	}
+
+	// signature needs the whole body
+	var payload []byte
+	if t.signer != nil && body != nil {
+		if payload, err = ioutil.ReadAll(body); err != nil {
+			return schema.Result{}, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(spec.Method, target, body)
+	if err != nil {
+		return schema.Result{}, err
+	}
+
+	for k, v := range spec.Header {
+		req.Header.Set(k, v)
+	}
+
+	if len(contentType) > 0 && len(req.Header.Get("Content-Type")) == 0 {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	td := schema.TracingData{
-		URL: t.Target,
+		URL: target,
+	}
+
+	if t.signer != nil {
+		if err := t.signer.Sign(req, payload); err != nil {
+			logrus.Errorf("request cannot be signed: %s", err.Error())
+			return schema.Result{TracingData: td, Failure: AuthFailure(err)}, nil
+		}
+	}
+	var cert *schema.Certificate
+
+	trace := NewClientTrace(&td, func(cs tls.ConnectionState) {
+		cert = CertificateFromState(cs, t.ExpiryThresholds)
+	})
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := t.Attacker.Do(req)
+	if err != nil {
+		td.FinishRequest = time.Now()
+		return t.FailedResult(td, err), nil
+	}
+
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, constants.MaxResponseBodySize))
+	td.FinishRequest = time.Now()
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return t.FailedResult(td, err), nil
 	}
 
-	trace := &httptrace.ClientTrace{
-		DNSStart:          func(dsi httptrace.DNSStartInfo) { td.DNSStart = time.Now() },
-		DNSDone:           func(ddi httptrace.DNSDoneInfo) { td.DNSDone = time.Now() },
+	result, err := t.NewResult(td, resp, respBody, spec.Checks)
+	if err != nil {
+		return schema.Result{}, err
+	}
+	result.Certificate = cert
+
+	return result, nil
+}
+
+// NewClientTrace returns client trace which records timings of each phase to td
+// handshakeDone is called with connection state when TLS handshake succeeds.
+func NewClientTrace(td *schema.TracingData, handshakeDone func(tls.ConnectionState)) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(dsi httptrace.DNSStartInfo) { td.DNSStart = time.Now() },
+		DNSDone: func(ddi httptrace.DNSDoneInfo) {
+			if ddi.Err == nil {
+				td.DNSDone = time.Now()
+			}
+		},
 		TLSHandshakeStart: func() { td.TLSHandshakeStart = time.Now() },
 		TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
-			if err != nil {
-				panic(err)
+			// handshake error is returned from the request and classified there
+			if err == nil {
+				td.TLSHandshakeDone = time.Now()
+				if handshakeDone != nil {
+					handshakeDone(cs)
+				}
 			}
-			td.TLSHandshakeDone = time.Now()
 		},
 		ConnectStart: func(network, addr string) {
 			if td.DNSDone.IsZero() {
@@ -151,42 +417,20 @@ func (t *Tracer) Trace() error {
 		},
 		ConnectDone: func(network, addr string, err error) {
 			td.ConnectAddr = addr
-			td.ConnectionDone = time.Now()
+			if err == nil {
+				td.ConnectionDone = time.Now()
+			}
 		},
 		GotConn: func(info httptrace.GotConnInfo) {
 			td.GotConn = time.Now()
+			td.Reused = info.Reused
+			td.WasIdle = info.WasIdle
 		},
 
 		GotFirstResponseByte: func() {
 			td.GetFirstResponseBtye = time.Now()
 		},
 	}
-
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
-
-	if err := t.SetupTransport(); err != nil {
-		return err
-	}
-
-	resp, err := t.Attacker.Do(req)
-	if err != nil {
-		if sendErr := t.SendErrorAlarm(err.Error()); sendErr != nil {
-			logrus.Errorln(sendErr)
-		}
-		return err
-	}
-
-	td.FinishRequest = time.Now()
-
-	if err := resp.Body.Close(); err != nil {
-		return err
-	}
-
-	if err := t.SetResult(td, resp); err != nil {
-		return err
-	}
-
-	return nil
 }
 
 // Run starts to trace request
@@ -194,6 +438,7 @@ func (t *Tracer) Run() error {
 	if err := t.Trace(); err != nil {
 		return err
 	}
+	RedactResult(&t.Result)
 
 	if t.LogLevel == "debug" {
 		if err := t.PrintResult(); err != nil {
@@ -201,10 +446,14 @@ func (t *Tracer) Run() error {
 		}
 	}
 
-	if len(t.SlackURL) > 0 && t.Result.Response.StatusCode != 200 {
+	if len(t.SlackURL) > 0 && t.Result.Failed() {
 		if err := t.SendAlarm(); err != nil {
 			return err
 		}
+	} else if len(t.SlackURL) > 0 && t.Result.Certificate != nil && t.Result.Certificate.Threshold > 0 {
+		if err := sendCertificateAlarm(t.SlackURL, t.Target, t.Region, t.Result.Certificate); err != nil {
+			return err
+		}
 	}
 
 	if err := t.SaveData(); err != nil {
@@ -219,6 +468,7 @@ func (t *Tracer) RunWithResult() (*schema.Result, error) {
 	if err := t.Trace(); err != nil {
 		return nil, err
 	}
+	RedactResult(&t.Result)
 
 	return &t.Result, nil
 }
@@ -249,10 +499,36 @@ func (t *Tracer) PrintResult() error {
 		return err
 	}
 
-	if err := t.DrawResultTable(); err != nil {
+	if len(t.Result.Steps) > 0 {
+		DrawStepTable(t.Result.Steps, t.Result.TracingData, t.Protocol == constants.HTTPS)
+	} else if err := t.DrawResultTable(); err != nil {
 		return err
 	}
 
+	if len(t.Result.Redirects) > 1 {
+		DrawRedirectTable(t.Result.Redirects)
+	}
+
+	if len(t.Result.Backends) > 0 {
+		DrawBackendTable(t.Result.Backends)
+	}
+
+	if t.Result.Warm != nil {
+		cold := t.Result
+		cold.Warm = nil
+		DrawWarmTable(cold, *t.Result.Warm)
+	}
+
+	if t.Result.Samples != nil {
+		DrawSampleTable(t.Result.Samples, t.Protocol == constants.HTTPS)
+	}
+
+	if len(t.Result.Attempts) > 1 {
+		DrawAttemptTable(t.Result.Attempts)
+	}
+
+	DrawAssertionTable(t.Result.Assertions)
+
 	return nil
 }
 
@@ -260,7 +536,6 @@ func (t *Tracer) PrintResult() error {
 func (t *Tracer) SendAlarm() error {
 	var attachments []slacker.Attachment
 	var blocks []slacker.Block
-	slack := slacker.NewSlackClient()
 
 	// TODO: create chart and upload it to S3
 	//filePath := "output.png"
@@ -298,21 +573,65 @@ func (t *Tracer) SendAlarm() error {
 		},
 	})
 
-	blocks = append(blocks, slacker.Block{
-		Type: "section",
-		Text: &slacker.Text{
-			Type: "mrkdwn",
-			Text: fmt.Sprintf("*Status Code*: %d", t.Result.Response.StatusCode),
-		},
-	})
+	if failure := t.Result.Failure; failure != nil {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Failure*: `%s` in %s phase\n%s", failure.Reason, failure.Phase, failure.Message),
+			},
+		})
+	}
 
-	blocks = append(blocks, slacker.Block{
-		Type: "section",
-		Text: &slacker.Text{
-			Type: "mrkdwn",
-			Text: fmt.Sprintf("*Status Message*: %s", t.Result.Response.StatusMsg),
-		},
-	})
+	if t.Result.Response.StatusCode > 0 {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Status Code*: %d", t.Result.Response.StatusCode),
+			},
+		})
+
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Status Message*: %s", t.Result.Response.StatusMsg),
+			},
+		})
+	}
+
+	if len(t.Result.Steps) > 0 {
+		blocks = append(blocks, stepBlock(t.Result.Steps))
+	}
+
+	if len(t.Result.Redirects) > 1 {
+		blocks = append(blocks, redirectBlock(t.Result.Redirects))
+	}
+
+	if len(t.Result.Backends) > 0 {
+		blocks = append(blocks, backendBlock(t.Result.Backends))
+	}
+
+	if t.Result.Warm != nil {
+		blocks = append(blocks, warmBlock(t.Result.TracingData, *t.Result.Warm))
+	}
+
+	if t.Result.Samples != nil {
+		blocks = append(blocks, sampleBlock(t.Result.Samples))
+	}
+
+	if len(t.Result.Attempts) > 1 {
+		blocks = append(blocks, attemptBlock(t.Result.Attempts))
+	}
+
+	if len(t.Result.Assertions) > 0 {
+		blocks = append(blocks, assertionBlock(t.Result.Assertions))
+	}
+
+	if t.Result.Certificate != nil && (!t.Result.Certificate.ChainVerified || t.Result.Certificate.Threshold > 0) {
+		blocks = append(blocks, certificateBlock(t.Result.Certificate))
+	}
 
 	// divider
 	blocks = append(blocks, slacker.Block{
@@ -364,71 +683,23 @@ func (t *Tracer) SendAlarm() error {
 		Fields: fields,
 	})
 
-	for _, URL := range t.SlackURL {
-		if err := slack.SendMessageWithWebHook(attachments, blocks, URL); err != nil {
-			fmt.Println(err.Error())
-			return err
-		}
-	}
-
-	return nil
+	return sendMessage(t.SlackURL, attachments, blocks)
 }
 
 // SendErrorAlarm sends error alarm
 func (t *Tracer) SendErrorAlarm(errorMsg string) error {
-	var attachments []slacker.Attachment
-	var blocks []slacker.Block
-	slack := slacker.NewSlackClient()
-
-	// title
-	blocks = append(blocks, slacker.Block{
-		Type: "section",
-		Text: &slacker.Text{
-			Type: "mrkdwn",
-			Text: fmt.Sprintf("Error occurred: `%s`", t.Target),
-		},
-	})
-
-	// divider
-	blocks = append(blocks, slacker.Block{
-		Type: "divider",
-	})
-
-	blocks = append(blocks, slacker.Block{
-		Type: "section",
-		Text: &slacker.Text{
-			Type: "mrkdwn",
-			Text: fmt.Sprintf("*%s*", t.Region),
-		},
-	})
-
-	blocks = append(blocks, slacker.Block{
-		Type: "section",
-		Text: &slacker.Text{
-			Type: "mrkdwn",
-			Text: fmt.Sprintf("*%s*", errorMsg),
-		},
-	})
-
-	for _, URL := range t.SlackURL {
-		if err := slack.SendMessageWithWebHook(attachments, blocks, URL); err != nil {
-			fmt.Println(err.Error())
-			return err
-		}
-	}
-
-	return nil
+	return SendErrorAlarm(t.SlackURL, t.Target, t.Region, errorMsg)
 }
 
-// SetResult sets the result to Tracker.Result
-func (t *Tracer) SetResult(td schema.TracingData, response *http.Response) error {
+// NewResult returns the result of response with assertions of checks
+func (t *Tracer) NewResult(td schema.TracingData, response *http.Response, body []byte, checks *schema.Checks) (schema.Result, error) {
 	res := schema.Response{}
 	var err error
 
 	// Parse status code
 	res.StatusCode, res.StatusMsg, err = ParseStatus(response.Status)
 	if err != nil {
-		return err
+		return schema.Result{}, err
 	}
 
 	header := map[string][]string{}
@@ -439,15 +710,31 @@ func (t *Tracer) SetResult(td schema.TracingData, response *http.Response) error
 	}
 
 	res.Header = header
+	res.Body = body
+	res.Protocol = NewProtocol(response)
 
 	td = Calculated(td, t.Protocol == constants.HTTPS)
 
-	t.Result = schema.Result{
+	assertions := checker.Assert(checks, res, td.Total)
+
+	return schema.Result{
 		TracingData: td,
 		Response:    res,
-	}
+		Assertions:  assertions,
+		Failure:     ClassifyAssertions(assertions),
+	}, nil
+}
 
-	return nil
+// FailedResult returns the result of failed request with timings up to the failure
+func (t *Tracer) FailedResult(td schema.TracingData, err error) schema.Result {
+	failure := ClassifyError(err, td)
+	logrus.Errorf("request failed in %s phase: %s", failure.Phase, failure.Reason)
+
+	return schema.Result{
+		TracingData: Calculated(td, t.Protocol == constants.HTTPS),
+		Failure:     failure,
+		Certificate: CertificateFromError(err, t.ExpiryThresholds),
+	}
 }
 
 // DrawResultTable draws result table of request
@@ -487,20 +774,38 @@ func (t *Tracer) DrawResultTable() error {
 }
 
 // Calculated calculates the durations of each step
+// Steps which are not finished are left as zero.
 func Calculated(td schema.TracingData, tls bool) schema.TracingData {
-	td.DNSLookup = td.DNSDone.Sub(td.DNSStart)
-	td.TCPConnection = td.ConnectionDone.Sub(td.ConnectionStart)
+	td.DNSLookup = elapsed(td.DNSStart, td.DNSDone)
+	td.TCPConnection = elapsed(td.ConnectionStart, td.ConnectionDone)
 	if tls {
-		td.TLSHandShacking = td.TLSHandshakeDone.Sub(td.TLSHandshakeStart)
+		td.TLSHandShacking = elapsed(td.TLSHandshakeStart, td.TLSHandshakeDone)
 	}
-	td.ServerProcessing = td.GetFirstResponseBtye.Sub(td.GotConn)
-	td.ContentTransfer = td.FinishRequest.Sub(td.GetFirstResponseBtye)
+	td.ServerProcessing = elapsed(td.GotConn, td.GetFirstResponseBtye)
+	td.ContentTransfer = elapsed(td.GetFirstResponseBtye, td.FinishRequest)
 
-	td.Total = td.FinishRequest.Sub(td.DNSStart)
+	// DNS lookup is skipped for IP address target
+	start := td.DNSStart
+	if start.IsZero() {
+		start = td.ConnectionStart
+	}
+	if start.IsZero() {
+		start = td.GotConn
+	}
+	td.Total = elapsed(start, td.FinishRequest)
 
 	return td
 }
 
+// elapsed returns duration between start and end if both are recorded
+func elapsed(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+
+	return end.Sub(start)
+}
+
 // ParseStatus parses status code and msg
 func ParseStatus(status string) (int, string, error) {
 	split := strings.Split(status, " ")
@@ -519,38 +824,32 @@ func ParseStatus(status string) (int, string, error) {
 }
 
 // SetupTransport sets transport configuration of request
-func (t *Tracer) SetupTransport() error {
-	parsed, err := url.Parse(t.Target)
-	if err != nil {
-		return err
-	}
-
+// Server name of TLS is left empty so that the host of each request including redirects is used.
+func (t *Tracer) SetupTransport(tlsConfig *tls.Config) error {
 	tr := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
 	}
 
-	if t.Protocol == constants.HTTPS {
-		host, _, err := net.SplitHostPort(parsed.Host)
-		if err != nil {
-			host = parsed.Host
-		}
-
-		tr.TLSClientConfig = &tls.Config{
-			ServerName:         host,
-			InsecureSkipVerify: false,
-			Certificates:       nil,
-		}
+	if len(t.pinnedIP) > 0 {
+		tr.DialContext = t.dialContext(&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		})
+	}
 
-		err = http2.ConfigureTransport(tr)
-		if err != nil {
+	if t.Protocol == constants.HTTPS {
+		if err := http2.ConfigureTransport(tr); err != nil {
 			return err
 		}
 	}
 
+	// idle connections of previous transport are not used any more
+	t.Attacker.CloseIdleConnections()
 	t.Attacker.Transport = tr
 	return nil
 }
@@ -562,6 +861,24 @@ func (t *Tracer) SaveData() error {
 		return err
 	}
 
+	for i, hop := range t.Result.Redirects {
+		if err := writer.WriteRedirectData("bigshot", "synthetics", t.Region, t.Target, i+1, hop); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range t.Result.Steps {
+		if err := writer.WriteStepData("bigshot", "synthetics", t.Region, t.Protocol, t.Target, step); err != nil {
+			return err
+		}
+	}
+
+	for _, backend := range t.Result.Backends {
+		if err := writer.WriteBackendData("bigshot", "synthetics", t.Region, t.Protocol, t.Target, backend); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -587,6 +904,84 @@ func (t *Tracer) SetHeader(m map[string]string) {
 	t.Header = m
 }
 
+// SetCertificateOption sets option of certificate probe
+func (t *Tracer) SetCertificateOption(option schema.CertificateOption) {
+	if len(option.ExpiryThresholds) > 0 {
+		t.ExpiryThresholds = option.ExpiryThresholds
+	}
+}
+
+// SetQuery sets query parameters of the request
+func (t *Tracer) SetQuery(query map[string]string) {
+	t.Query = query
+}
+
+// SetPayload sets payload of the request
+func (t *Tracer) SetPayload(payload *schema.Payload) {
+	t.Payload = payload
+}
+
+// SetAuth sets authentication of requests
+func (t *Tracer) SetAuth(option schema.AuthOption) {
+	t.Auth = &option
+}
+
+// SetSigV4Option enables signing requests with AWS Signature Version 4
+func (t *Tracer) SetSigV4Option(option schema.SigV4Option) {
+	t.SigV4 = &option
+}
+
+// SetupSigner creates signer of the trace and gets credentials for it
+func (t *Tracer) SetupSigner() error {
+	t.signer = nil
+	if t.SigV4 == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(t.Target)
+	if err != nil {
+		return err
+	}
+
+	signer, err := auth.NewSigV4(*t.SigV4, parsed.Hostname())
+	if err != nil {
+		return err
+	}
+
+	if err := signer.Retrieve(); err != nil {
+		return fmt.Errorf("credentials for sigv4 cannot be retrieved: %s", err.Error())
+	}
+	t.signer = signer
+
+	return nil
+}
+
+// FailBeforeRequest sets result of failure which happened before request is sent
+func (t *Tracer) FailBeforeRequest(failure *schema.Failure) {
+	logrus.Errorf("request cannot be sent: %s", failure.Message)
+	t.Result = schema.Result{
+		TracingData: schema.TracingData{
+			URL: t.Target,
+		},
+		Failure: failure,
+	}
+}
+
+// SetTLSOption sets TLS option of requests
+func (t *Tracer) SetTLSOption(option schema.TLSOption) {
+	t.TLS = &option
+}
+
+// SetRedirectOption enables following redirects
+func (t *Tracer) SetRedirectOption(option schema.RedirectOption) {
+	t.Redirect = &option
+}
+
+// SetChecks sets assertions on response
+func (t *Tracer) SetChecks(checks *schema.Checks) {
+	t.Checks = checks
+}
+
 // NewTracer creates tracer test
 func NewTracer(region string) Shooter {
 	return &Tracer{
@@ -596,8 +991,9 @@ func NewTracer(region string) Shooter {
 				return http.ErrUseLastResponse
 			},
 		},
-		Duration: constants.DefaultWorkerDuration,
-		Region:   region,
-		Target:   constants.EmptyString,
+		Duration:         constants.DefaultWorkerDuration,
+		Region:           region,
+		Target:           constants.EmptyString,
+		ExpiryThresholds: constants.DefaultExpiryThresholds,
 	}
 }
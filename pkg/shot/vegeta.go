@@ -18,91 +18,515 @@ package shot
 
 import (
 	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
 	"github.com/DevopsArtFactory/bigshot/pkg/constants"
 	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
 )
 
 type Vegeta struct {
-	Name     string
-	Attacker *vegeta.Attacker
-	Rate     *vegeta.Rate
-	Duration time.Duration
-	SlackURL []string
-	Targeter vegeta.Targeter
+	Name       string
+	Rate       *vegeta.Rate
+	Duration   time.Duration
+	Timeout    time.Duration
+	MaxWorkers uint64
+	MinSuccess float64
+	Target     string
+	Method     string
+	Body       map[string]string
+	Payload    *schema.Payload
+	Query      map[string]string
+	Header     map[string]string
+	Auth       *schema.AuthOption
+	TLS        *schema.TLSOption
+	Region     string
+	SlackURL   []string
+	LogLevel   string
+	Result     schema.Result
 }
 
+// NewVegeta creates a new vegeta client
+func NewVegeta(region string) Shooter {
+	return &Vegeta{
+		Name:       fmt.Sprintf("Request from %s", region),
+		Duration:   constants.DefaultLoadDuration,
+		MaxWorkers: vegeta.DefaultMaxWorkers,
+		MinSuccess: constants.DefaultMinSuccess,
+		Method:     http.MethodGet,
+		Region:     region,
+		Target:     constants.EmptyString,
+		Rate: &vegeta.Rate{
+			Freq: constants.DefaultLoadRate,
+			Per:  time.Second,
+		},
+	}
+}
+
+// SetSlackURL set slack URL for notification
+func (v *Vegeta) SetSlackURL(s []string) {
+	v.SlackURL = s
+}
+
+// SetRate sets rate for api call
+func (v *Vegeta) SetRate(freq int) {
+	if freq > 0 {
+		v.Rate.Freq = freq
+	}
+}
+
+// SetTimeout sets timeout of each request
 func (v *Vegeta) SetTimeout(i int) {
-	panic("implement me")
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	v.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
 }
 
-func (v *Vegeta) SetLogLevel(s string) {
-	panic("implement me")
+// SetLogLevel sets loglevel
+func (v *Vegeta) SetLogLevel(logLevel string) {
+	v.LogLevel = logLevel
 }
 
-func (v *Vegeta) RunWithResult() (*schema.Result, error) {
-	panic("implement me")
+// SetTarget sets target for api call
+func (v *Vegeta) SetTarget(url, port string) {
+	_, v.Target = GetTargetURL(url, port)
+	logrus.Infof("target is registered: %s", v.Target)
 }
 
+// SetMethod sets method of API
 func (v *Vegeta) SetMethod(s string) {
-	panic("implement me")
+	if len(s) > 0 {
+		v.Method = s
+	}
 }
 
+// SetBody sets body data
 func (v *Vegeta) SetBody(m map[string]string) {
-	panic("implement me")
+	v.Body = m
 }
 
+// SetHeader sets header data
 func (v *Vegeta) SetHeader(m map[string]string) {
-	panic("implement me")
+	v.Header = m
 }
 
-// NewVegeta creates a new vegeta client
-func NewVegeta(region string) Shooter {
-	return &Vegeta{
-		Name:     fmt.Sprintf("Request from %s", region),
-		Attacker: vegeta.NewAttacker(),
-		Duration: constants.DefaultWorkerDuration,
-		Targeter: nil,
-		Rate: &vegeta.Rate{
-			Freq: 0,
-			Per:  time.Second,
-		},
-	}
+// SetQuery sets query parameters of API
+func (v *Vegeta) SetQuery(query map[string]string) {
+	v.Query = query
 }
 
-// SetSlackURL set slack URL for notification
-func (v *Vegeta) SetSlackURL(s []string) {
-	v.SlackURL = s
+// SetPayload sets payload of API
+func (v *Vegeta) SetPayload(payload *schema.Payload) {
+	v.Payload = payload
 }
 
-// SetRate sets rate for api call
-func (v *Vegeta) SetRate(freq int) {
-	v.Rate.Freq = freq
+// SetAuth sets authentication of requests
+// Credentials are obtained once before attack.
+func (v *Vegeta) SetAuth(option schema.AuthOption) {
+	v.Auth = &option
 }
 
-// SetTarget sets target for api call
-func (v *Vegeta) SetTarget(url, port string) {
-	v.Targeter = vegeta.NewStaticTargeter(vegeta.Target{
-		Method: "GET",
+// SetTLSOption sets TLS option of requests
+func (v *Vegeta) SetTLSOption(option schema.TLSOption) {
+	v.TLS = &option
+}
+
+// SetOption sets load test specific options
+func (v *Vegeta) SetOption(option schema.LoadOption) {
+	if option.Rate != nil {
+		v.SetRate(*option.Rate)
+	}
+
+	if option.Duration != nil && *option.Duration > 0 {
+		v.Duration = time.Duration(*option.Duration) * time.Second
+	}
+
+	if option.MaxWorkers != nil && *option.MaxWorkers > 0 {
+		v.MaxWorkers = uint64(*option.MaxWorkers)
+	}
+
+	if option.MinSuccess != nil {
+		v.MinSuccess = *option.MinSuccess
+	}
+}
+
+// Targeter creates a targeter for vegeta attack
+func (v *Vegeta) Targeter() (vegeta.Targeter, error) {
+	url, err := WithQuery(v.Target, v.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	target := vegeta.Target{
+		Method: v.Method,
 		URL:    url,
-	})
+		Header: http.Header{},
+	}
+
+	body, contentType, err := NewRequestBody(v.Body, v.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		target.Body = b
+		target.Header.Set("Content-Type", contentType)
+	}
 
-	logrus.Infof("target is registered: %s", url)
+	header, err := AuthorizedHeader(v.Header, v.Auth, v.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("credentials cannot be obtained: %s", err.Error())
+	}
+
+	for k, val := range header {
+		target.Header.Set(k, val)
+	}
+
+	return vegeta.NewStaticTargeter(target), nil
 }
 
-// Shoot runs api request
-func (v *Vegeta) Run() error {
+// Attack runs load test and collects metrics
+func (v *Vegeta) Attack() error {
+	targeter, err := v.Targeter()
+	if err != nil {
+		return err
+	}
+
+	var opts []func(*vegeta.Attacker)
+	opts = append(opts, vegeta.MaxWorkers(v.MaxWorkers))
+	if v.Timeout > 0 {
+		opts = append(opts, vegeta.Timeout(v.Timeout))
+	}
+
+	// server certificate is verified unless insecure_skip_verify is set like other shooters
+	option := v.TLS
+	if option == nil {
+		option = &schema.TLSOption{}
+	}
+	tlsConfig, err := NewTLSConfig(option)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, vegeta.TLSConfig(tlsConfig))
+	attacker := vegeta.NewAttacker(opts...)
+
 	var metrics vegeta.Metrics
-	for res := range v.Attacker.Attack(v.Targeter, v.Rate, v.Duration, v.Name) {
+	histogram := NewLatencyHistogram()
+	errs := map[string]int{}
+	for res := range attacker.Attack(targeter, v.Rate, v.Duration, v.Name) {
 		metrics.Add(res)
+		RecordLatency(histogram, res.Latency)
+		if len(res.Error) > 0 {
+			errs[res.Error]++
+		}
 	}
 	metrics.Close()
 
-	logrus.Infof("%+v", metrics)
+	encoded, err := histogram.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return err
+	}
+
+	v.SetResult(metrics, errs, string(encoded))
+
+	return nil
+}
+
+// NewLatencyHistogram creates a histogram of latencies in microseconds
+func NewLatencyHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(1, constants.HistogramMaxLatency.Microseconds(), constants.HistogramSignificantFigures)
+}
+
+// RecordLatency records latency to histogram
+func RecordLatency(histogram *hdrhistogram.Histogram, latency time.Duration) {
+	value := latency.Microseconds()
+	if value > histogram.HighestTrackableValue() {
+		value = histogram.HighestTrackableValue()
+	}
+
+	if err := histogram.RecordValue(value); err != nil {
+		logrus.Debugf("latency is not recorded: %s", err.Error())
+	}
+}
+
+// Run runs load test
+func (v *Vegeta) Run() error {
+	if err := v.Attack(); err != nil {
+		if sendErr := SendErrorAlarm(v.SlackURL, v.Target, v.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&v.Result)
+
+	if v.LogLevel == "debug" {
+		if err := v.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(v.SlackURL) > 0 && v.Result.Failed() {
+		if err := v.SendAlarm(); err != nil {
+			return err
+		}
+	}
+
+	if err := v.SaveData(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunWithResult runs load test and returns result
+func (v *Vegeta) RunWithResult() (*schema.Result, error) {
+	if err := v.Attack(); err != nil {
+		return nil, err
+	}
+	RedactResult(&v.Result)
+
+	return &v.Result, nil
+}
+
+// SetResult sets the metrics to Vegeta.Result
+func (v *Vegeta) SetResult(metrics vegeta.Metrics, errs map[string]int, histogram string) {
+	v.Result = schema.Result{
+		Load: &schema.LoadMetrics{
+			URL:        v.Target,
+			Method:     v.Method,
+			Rate:       metrics.Rate,
+			Duration:   metrics.Duration,
+			Requests:   metrics.Requests,
+			Throughput: metrics.Throughput,
+			Success:    metrics.Success,
+			MinSuccess: v.MinSuccess,
+			Latencies: schema.Latencies{
+				Mean: metrics.Latencies.Mean,
+				P50:  metrics.Latencies.P50,
+				P90:  metrics.Latencies.P90,
+				P95:  metrics.Latencies.P95,
+				P99:  metrics.Latencies.P99,
+				Max:  metrics.Latencies.Max,
+			},
+			StatusCodes: metrics.StatusCodes,
+			Errors:      errs,
+			Histogram:   histogram,
+		},
+	}
+}
+
+// PrintResult prints result
+func (v *Vegeta) PrintResult() error {
+	var scanData = struct {
+		Summary schema.LoadMetrics
+	}{
+		Summary: *v.Result.Load,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate":    color.DecorateAttr,
+		"format":      tools.Formatting,
+		"statusCodes": FormatStatusCodes,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.LoadTemplate))
+
+	if err := tt.Execute(w, scanData); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	DrawLatencyTable(v.Result.Load.Latencies)
+
+	return nil
+}
+
+// DrawLatencyTable draws latency percentiles table
+func DrawLatencyTable(latencies schema.Latencies) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Mean", "P50", "P90", "P95", "P99", "Max"})
+	table.Append([]string{
+		latencies.Mean.String(),
+		latencies.P50.String(),
+		latencies.P90.String(),
+		latencies.P95.String(),
+		latencies.P99.String(),
+		latencies.Max.String(),
+	})
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// SendAlarm sends slack alarm
+func (v *Vegeta) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	metrics := v.Result.Load
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", v.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Load Test*: `%s %s`", metrics.Method, metrics.URL),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Success*: %.2f%% (minimum %.2f%%)", metrics.Success*100, metrics.MinSuccess*100),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Status Codes*: %s", FormatStatusCodes(metrics.StatusCodes)),
+		},
+	})
+
+	if len(metrics.Errors) > 0 {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Errors*: %s", FormatErrors(metrics.Errors)),
+			},
+		})
+	}
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	fields := []slacker.Field{
+		{
+			Title: "Requests",
+			Value: fmt.Sprintf("%d (%.2f/s)", metrics.Requests, metrics.Rate),
+			Short: true,
+		},
+		{
+			Title: "Throughput",
+			Value: fmt.Sprintf("%.2f/s", metrics.Throughput),
+			Short: true,
+		},
+		{
+			Title: "P50",
+			Value: metrics.Latencies.P50.String(),
+			Short: true,
+		},
+		{
+			Title: "P90",
+			Value: metrics.Latencies.P90.String(),
+			Short: true,
+		},
+		{
+			Title: "P95",
+			Value: metrics.Latencies.P95.String(),
+			Short: true,
+		},
+		{
+			Title: "P99",
+			Value: metrics.Latencies.P99.String(),
+			Short: true,
+		},
+		{
+			Title: "Max",
+			Value: metrics.Latencies.Max.String(),
+			Short: true,
+		},
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color:  constants.ErrorColor,
+		Text:   fmt.Sprintf("*Load test result* - Duration: %s", metrics.Duration.String()),
+		Fields: fields,
+	})
+
+	return sendMessage(v.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (v *Vegeta) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WriteLoadData("bigshot", "synthetics", v.Region, *v.Result.Load); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// FormatStatusCodes returns status code histogram as string
+func FormatStatusCodes(codes map[string]int) string {
+	var ret []string
+	for _, code := range sortedKeys(codes) {
+		ret = append(ret, fmt.Sprintf("%s:%d", code, codes[code]))
+	}
+
+	return strings.Join(ret, " ")
+}
+
+// FormatErrors returns error counts as string
+func FormatErrors(errs map[string]int) string {
+	var ret []string
+	for _, e := range sortedKeys(errs) {
+		ret = append(ret, fmt.Sprintf("%s (%d)", e, errs[e]))
+	}
+
+	return strings.Join(ret, ", ")
+}
+
+// sortedKeys returns sorted keys of counts
+func sortedKeys(counts map[string]int) []string {
+	var keys []string
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
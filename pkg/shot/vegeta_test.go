@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+// newMetrics returns closed metrics of results with latencies of 1ms to n ms
+// Every failed-th result fails with 500.
+func newMetrics(n, failed int) vegeta.Metrics {
+	var metrics vegeta.Metrics
+	start := time.Now()
+	for i := 1; i <= n; i++ {
+		res := vegeta.Result{
+			Code:      200,
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Millisecond),
+			Latency:   time.Duration(i) * time.Millisecond,
+		}
+		if failed > 0 && i%failed == 0 {
+			res.Code = 500
+			res.Error = "500 Internal Server Error"
+		}
+		metrics.Add(&res)
+	}
+	metrics.Close()
+
+	return metrics
+}
+
+func TestVegetaSetResult(t *testing.T) {
+	testData := []struct {
+		Name        string
+		Requests    int
+		FailedEvery int
+		MinSuccess  float64
+		Success     float64
+		StatusCodes map[string]int
+		Failed      bool
+	}{
+		{Name: "every request succeeded", Requests: 100, MinSuccess: 1, Success: 1, StatusCodes: map[string]int{"200": 100}, Failed: false},
+		{Name: "success over minimum", Requests: 100, FailedEvery: 10, MinSuccess: 0.9, Success: 0.9, StatusCodes: map[string]int{"200": 90, "500": 10}, Failed: false},
+		{Name: "success under minimum", Requests: 100, FailedEvery: 4, MinSuccess: 0.9, Success: 0.75, StatusCodes: map[string]int{"200": 75, "500": 25}, Failed: true},
+		{Name: "no request", Requests: 0, MinSuccess: 0, StatusCodes: map[string]int{}, Failed: true},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			v := NewVegeta("test").(*Vegeta)
+			v.SetOption(schema.LoadOption{MinSuccess: aws.Float64(td.MinSuccess)})
+			v.SetResult(newMetrics(td.Requests, td.FailedEvery), map[string]int{}, "")
+
+			load := v.Result.Load
+			if load.Requests != uint64(td.Requests) || load.Success != td.Success || load.MinSuccess != td.MinSuccess {
+				t.Errorf("expected requests: %d, success: %v, min success: %v, got: %+v", td.Requests, td.Success, td.MinSuccess, load)
+			}
+
+			if len(load.StatusCodes) != len(td.StatusCodes) {
+				t.Errorf("expected status codes: %v, got: %v", td.StatusCodes, load.StatusCodes)
+			}
+			for code, count := range td.StatusCodes {
+				if load.StatusCodes[code] != count {
+					t.Errorf("expected status codes: %v, got: %v", td.StatusCodes, load.StatusCodes)
+				}
+			}
+
+			// latencies of 1ms to 100ms are interpolated between neighbors
+			if td.Requests > 0 {
+				expected := schema.Latencies{
+					Mean: 50500 * time.Microsecond,
+					P50:  50500 * time.Microsecond,
+					P90:  90500 * time.Microsecond,
+					P95:  95500 * time.Microsecond,
+					P99:  99500 * time.Microsecond,
+					Max:  100 * time.Millisecond,
+				}
+				if load.Latencies != expected {
+					t.Errorf("expected latencies: %+v, got: %+v", expected, load.Latencies)
+				}
+			}
+
+			if v.Result.Failed() != td.Failed {
+				t.Errorf("expected failed: %t, got: %t", td.Failed, v.Result.Failed())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+)
+
+// SetWarm sets whether the second request is sent on connection of the first one
+func (t *Tracer) SetWarm(warm bool) {
+	t.Warm = warm
+}
+
+// DrawWarmTable draws timings and results of cold and warm requests
+func DrawWarmTable(cold, warm schema.Result) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Connection", "DNS Lookup", "TCP Connection", "TLS Handshake", "Server Processing", "Content Transfer", "Total", "Reused", "Was Idle", "Result"})
+	for _, row := range []struct {
+		Name   string
+		Result schema.Result
+	}{
+		{Name: "cold", Result: cold},
+		{Name: "warm", Result: warm},
+	} {
+		data := row.Result.TracingData
+		table.Append([]string{
+			row.Name,
+			data.DNSLookup.String(),
+			data.TCPConnection.String(),
+			data.TLSHandShacking.String(),
+			data.ServerProcessing.String(),
+			data.ContentTransfer.String(),
+			data.Total.String(),
+			strconv.FormatBool(data.Reused),
+			strconv.FormatBool(data.WasIdle),
+			backendResult(row.Result),
+		})
+	}
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetRowLine(true)
+	table.Render()
+}
+
+// warmBlock returns slack block of cold and warm timings
+func warmBlock(cold schema.TracingData, warm schema.Result) slacker.Block {
+	text := fmt.Sprintf("*Cold*: %s\n*Warm*: %s (status: %s, reused: %t)",
+		cold.Total.String(),
+		warm.TracingData.Total.String(),
+		backendStatus(warm),
+		warm.TracingData.Reused,
+	)
+
+	if warm.Failure != nil {
+		text += fmt.Sprintf("\n*Warm Failure*: %s (%s) `%s`", warm.Failure.Reason, warm.Failure.Phase, warm.Failure.Message)
+	}
+
+	return slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: text,
+		},
+	}
+}
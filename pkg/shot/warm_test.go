@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWarmRequest(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the second request of each check fails when path is /flaky
+		if atomic.AddInt32(&count, 1)%2 == 0 && r.URL.Path == "/flaky" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testData := []struct {
+		Name       string
+		Path       string
+		WarmStatus int
+		Failed     bool
+	}{
+		{Name: "warm request reuses connection", Path: "/", WarmStatus: http.StatusOK, Failed: false},
+		{Name: "failed warm request keeps cold result", Path: "/flaky", WarmStatus: http.StatusServiceUnavailable, Failed: true},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			atomic.StoreInt32(&count, 0)
+
+			tracer := newTestTracer(server.URL + td.Path)
+			tracer.SetWarm(true)
+			if err := tracer.Trace(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := tracer.Result
+			if result.Response.StatusCode != http.StatusOK || result.TracingData.Reused {
+				t.Errorf("cold request should pass on a new connection: %d, reused: %t", result.Response.StatusCode, result.TracingData.Reused)
+			}
+
+			if result.Warm == nil {
+				t.Fatal("warm result is not recorded")
+			}
+
+			warm := result.Warm
+			if !warm.TracingData.Reused || !warm.TracingData.WasIdle {
+				t.Errorf("warm request should reuse idle connection: reused: %t, was idle: %t", warm.TracingData.Reused, warm.TracingData.WasIdle)
+			}
+
+			if warm.Response.StatusCode != td.WarmStatus {
+				t.Errorf("expected warm status: %d, got: %d", td.WarmStatus, warm.Response.StatusCode)
+			}
+
+			if result.Failed() != td.Failed {
+				t.Errorf("expected failed: %t, got: %t", td.Failed, result.Failed())
+			}
+		})
+	}
+}
@@ -0,0 +1,561 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/checker"
+	"github.com/DevopsArtFactory/bigshot/pkg/client"
+	"github.com/DevopsArtFactory/bigshot/pkg/color"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/slacker"
+	"github.com/DevopsArtFactory/bigshot/pkg/templates"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+type WebSocket struct {
+	Name           string
+	Host           string
+	Port           string
+	Path           string
+	TLS            bool
+	Header         map[string]string
+	Auth           *schema.AuthOption
+	Options        *schema.TLSOption
+	Message        string
+	Expected       string
+	MessageTimeout time.Duration
+	Timeout        time.Duration
+	Region         string
+	SlackURL       []string
+	LogLevel       string
+	Result         schema.Result
+
+	// ExpiryThresholds are days before certificate expiry to send alarm
+	ExpiryThresholds []int
+}
+
+// NewWebSocket creates WebSocket probe
+func NewWebSocket(region string) Shooter {
+	return &WebSocket{
+		Name:    fmt.Sprintf("Request from %s", region),
+		Path:    "/",
+		Timeout: time.Duration(constants.DefaultTargetTimeout) * time.Second,
+		Region:  region,
+
+		ExpiryThresholds: constants.DefaultExpiryThresholds,
+	}
+}
+
+// SetSlackURL set slack URL for notification
+func (w *WebSocket) SetSlackURL(s []string) {
+	w.SlackURL = s
+}
+
+// SetRate is not used for WebSocket
+func (w *WebSocket) SetRate(freq int) {}
+
+// SetTimeout sets timeout of upgrade handshake
+func (w *WebSocket) SetTimeout(i int) {
+	if i == 0 {
+		i = constants.DefaultTargetTimeout
+	}
+	w.Timeout = time.Duration(i) * time.Second
+	logrus.Infof("Timeout: %d", i)
+}
+
+// SetLogLevel sets loglevel
+func (w *WebSocket) SetLogLevel(logLevel string) {
+	w.LogLevel = logLevel
+}
+
+// SetTarget sets target host and port
+// URL can be a full URL with ws, wss, http or https scheme.
+func (w *WebSocket) SetTarget(url, port string) {
+	u, err := tools.ParseTargetURL(url, port)
+	if err != nil {
+		logrus.Errorf("URL of target is not correct: %s", err.Error())
+		w.Host, w.Port, w.TLS = parseHost(url), port, port == constants.DefaultHTTPSPort
+		return
+	}
+
+	w.Host = u.Hostname()
+	w.TLS = tools.IsSecureScheme(u.Scheme)
+	w.Port = u.Port()
+	if len(w.Port) == 0 {
+		w.Port = tools.DefaultPort(u.Scheme)
+	}
+
+	if len(u.Path) > 0 {
+		w.Path = u.RequestURI()
+	}
+	logrus.Infof("Target: %s, Protocol: WebSocket", w.URL())
+}
+
+// SetMethod is not used for WebSocket
+func (w *WebSocket) SetMethod(s string) {}
+
+// SetBody is not used for WebSocket
+func (w *WebSocket) SetBody(m map[string]string) {}
+
+// SetHeader sets headers of upgrade request
+func (w *WebSocket) SetHeader(m map[string]string) {
+	w.Header = m
+}
+
+// SetAuth sets authentication of upgrade request
+func (w *WebSocket) SetAuth(option schema.AuthOption) {
+	w.Auth = &option
+}
+
+// SetTLSOption sets TLS option of connection
+func (w *WebSocket) SetTLSOption(option schema.TLSOption) {
+	w.Options = &option
+}
+
+// SetCertificateOption sets certificate checking option
+func (w *WebSocket) SetCertificateOption(option schema.CertificateOption) {
+	if len(option.ExpiryThresholds) > 0 {
+		w.ExpiryThresholds = option.ExpiryThresholds
+	}
+}
+
+// SetOption sets WebSocket specific options
+func (w *WebSocket) SetOption(option schema.WebSocketOption) {
+	if option.Path != nil {
+		w.Path = *option.Path
+		if !strings.HasPrefix(w.Path, "/") {
+			w.Path = "/" + w.Path
+		}
+	}
+
+	if option.Message != nil {
+		w.Message = *option.Message
+	}
+
+	if option.Expected != nil {
+		w.Expected = *option.Expected
+	}
+
+	if option.MessageTimeout != nil {
+		w.MessageTimeout = time.Duration(*option.MessageTimeout) * time.Second
+	}
+}
+
+// Secure returns whether target uses TLS
+func (w *WebSocket) Secure() bool {
+	return w.TLS
+}
+
+// URL returns URL of upgrade request
+func (w *WebSocket) URL() string {
+	scheme := "ws"
+	if w.Secure() {
+		scheme = "wss"
+	}
+
+	host := net.JoinHostPort(w.Host, w.Port)
+	if w.Port == tools.DefaultPort(scheme) {
+		host = strings.TrimSuffix(host, ":"+w.Port)
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, w.Path)
+}
+
+// Probe upgrades connection to WebSocket and checks message round-trip
+func (w *WebSocket) Probe() error {
+	var expected *regexp.Regexp
+	if len(w.Expected) > 0 {
+		re, err := regexp.Compile(w.Expected)
+		if err != nil {
+			return err
+		}
+		expected = re
+	}
+
+	td := schema.TracingData{
+		URL: w.URL(),
+	}
+	result := schema.WebSocketResult{
+		URL: w.URL(),
+	}
+	var cert *schema.Certificate
+
+	// DNS, TCP and TLS timings are recorded in the same way as http type
+	trace := NewClientTrace(&td, func(cs tls.ConnectionState) {
+		cert = CertificateFromState(cs, w.ExpiryThresholds)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), w.Timeout)
+	defer cancel()
+	ctx = httptrace.WithClientTrace(ctx, trace)
+
+	authorized, err := AuthorizedHeader(w.Header, w.Auth, w.Timeout)
+	if err != nil {
+		logrus.Errorf("credentials cannot be obtained: %s", err.Error())
+		w.SetFailure(td, result, AuthFailure(err))
+		return nil
+	}
+
+	header := http.Header{}
+	for k, v := range authorized {
+		header.Set(k, v)
+	}
+
+	tlsConfig, err := NewTLSConfig(w.Options)
+	if err != nil {
+		w.SetFailure(td, result, TLSConfigFailure(err))
+		return nil
+	}
+	tlsConfig.ServerName = w.Host
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: w.Timeout,
+		TLSClientConfig:  tlsConfig,
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, w.URL(), header)
+	upgraded := time.Now()
+
+	// Connection is handed over before TLS handshake in WebSocket dialer
+	if td.TLSHandshakeDone.After(td.GotConn) {
+		td.GotConn = td.TLSHandshakeDone
+	}
+
+	var response schema.Response
+	if resp != nil {
+		result.UpgradeLatency = elapsed(td.GotConn, upgraded)
+		response.StatusCode, response.StatusMsg, _ = ParseStatus(resp.Status)
+		response.Header = resp.Header
+	}
+
+	if err != nil {
+		td.FinishRequest = upgraded
+		if resp != nil {
+			// Server responded without switching protocols
+			assertions := []schema.Assertion{upgradeAssertion(response.StatusCode)}
+			w.Result = schema.Result{
+				TracingData: Calculated(td, w.Secure()),
+				Response:    response,
+				WebSocket:   &result,
+				Assertions:  assertions,
+				Failure:     ClassifyAssertions(assertions),
+				Certificate: cert,
+			}
+			return nil
+		}
+
+		w.SetFailure(td, result, ClassifyError(err, td))
+		w.Result.Certificate = CertificateFromError(err, w.ExpiryThresholds)
+		return nil
+	}
+	defer conn.Close()
+	result.Subprotocol = conn.Subprotocol()
+
+	assertions := []schema.Assertion{upgradeAssertion(response.StatusCode)}
+	if len(w.Message) > 0 || expected != nil {
+		reply, failure := w.roundTrip(conn, expected, &result)
+		if failure != nil {
+			w.SetFailure(td, result, failure)
+			w.Result.Response = response
+			w.Result.Certificate = cert
+			return nil
+		}
+
+		if expected != nil {
+			assertions = append(assertions, checker.AssertRegex("reply regex", w.Expected, reply))
+		}
+	}
+	td.FinishRequest = time.Now()
+
+	// close handshake is not part of the probe
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+
+	w.Result = schema.Result{
+		TracingData: Calculated(td, w.Secure()),
+		Response:    response,
+		WebSocket:   &result,
+		Assertions:  assertions,
+		Failure:     ClassifyAssertions(assertions),
+		Certificate: cert,
+	}
+
+	return nil
+}
+
+// roundTrip sends message and reads replies until one matches expected
+func (w *WebSocket) roundTrip(conn *websocket.Conn, expected *regexp.Regexp, result *schema.WebSocketResult) ([]byte, *schema.Failure) {
+	timeout := w.MessageTimeout
+	if timeout == 0 {
+		timeout = w.Timeout
+	}
+	deadline := time.Now().Add(timeout)
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return nil, messageFailure(err)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, messageFailure(err)
+	}
+
+	sent := time.Now()
+	if len(w.Message) > 0 {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(w.Message)); err != nil {
+			return nil, messageFailure(err)
+		}
+	}
+
+	if expected == nil {
+		return nil, nil
+	}
+
+	var reply []byte
+	received := false
+	for !received || !expected.Match(reply) {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !received {
+				return nil, messageFailure(err)
+			}
+			break
+		}
+		received = true
+		reply = data
+		result.Reply = string(reply)
+		if len(result.Reply) > maxTCPResponseDisplay {
+			result.Reply = result.Reply[:maxTCPResponseDisplay]
+		}
+	}
+	result.RoundTrip = time.Since(sent)
+
+	return reply, nil
+}
+
+// messageFailure returns failure of WebSocket message round-trip
+func messageFailure(err error) *schema.Failure {
+	reason := constants.ReasonMessageError
+	if isTimeout(err) {
+		reason = constants.ReasonMessageTimeout
+	}
+
+	return &schema.Failure{
+		Phase:   constants.PhaseMessage,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
+
+// upgradeAssertion checks whether server switched protocols
+func upgradeAssertion(statusCode int) schema.Assertion {
+	return schema.Assertion{
+		Name:     "status",
+		Expected: tools.IntToString(http.StatusSwitchingProtocols),
+		Actual:   tools.IntToString(statusCode),
+		Passed:   statusCode == http.StatusSwitchingProtocols,
+	}
+}
+
+// SetFailure sets the result of failed probe with timings up to the failure
+func (w *WebSocket) SetFailure(td schema.TracingData, result schema.WebSocketResult, failure *schema.Failure) {
+	if td.FinishRequest.IsZero() {
+		td.FinishRequest = time.Now()
+	}
+	logrus.Errorf("WebSocket probe failed in %s phase: %s", failure.Phase, failure.Reason)
+
+	w.Result = schema.Result{
+		TracingData: Calculated(td, w.Secure()),
+		WebSocket:   &result,
+		Failure:     failure,
+	}
+}
+
+// Run starts WebSocket probe
+func (w *WebSocket) Run() error {
+	if err := w.Probe(); err != nil {
+		if sendErr := SendErrorAlarm(w.SlackURL, w.URL(), w.Region, err.Error()); sendErr != nil {
+			logrus.Errorln(sendErr)
+		}
+		return err
+	}
+	RedactResult(&w.Result)
+
+	if w.LogLevel == "debug" {
+		if err := w.PrintResult(); err != nil {
+			return err
+		}
+	}
+
+	if len(w.SlackURL) > 0 && w.Result.Failed() {
+		if err := w.SendAlarm(); err != nil {
+			return err
+		}
+	} else if len(w.SlackURL) > 0 && w.Result.Certificate != nil && w.Result.Certificate.Threshold > 0 {
+		if err := sendCertificateAlarm(w.SlackURL, w.URL(), w.Region, w.Result.Certificate); err != nil {
+			return err
+		}
+	}
+
+	if err := w.SaveData(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunWithResult runs WebSocket probe and returns result
+func (w *WebSocket) RunWithResult() (*schema.Result, error) {
+	if err := w.Probe(); err != nil {
+		return nil, err
+	}
+	RedactResult(&w.Result)
+
+	return &w.Result, nil
+}
+
+// PrintResult prints result
+func (w *WebSocket) PrintResult() error {
+	var scanData = struct {
+		Summary schema.Result
+	}{
+		Summary: w.Result,
+	}
+
+	funcMap := template.FuncMap{
+		"decorate": color.DecorateAttr,
+		"format":   tools.Formatting,
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	tt := template.Must(template.New("Result").Funcs(funcMap).Parse(templates.WebSocketTemplate))
+
+	if err := tt.Execute(tw, scanData); err != nil {
+		return err
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	header := []string{"DNS Lookup", "TCP Connection"}
+	durations := []time.Duration{w.Result.TracingData.DNSLookup, w.Result.TracingData.TCPConnection}
+	if w.Secure() {
+		header = append(header, "TLS Handshake")
+		durations = append(durations, w.Result.TracingData.TLSHandShacking)
+	}
+	header = append(header, "Upgrade", "Round Trip", "Total")
+	durations = append(durations, w.Result.WebSocket.UpgradeLatency, w.Result.WebSocket.RoundTrip, w.Result.TracingData.Total)
+
+	DrawTimingTable(header, durations)
+	DrawAssertionTable(w.Result.Assertions)
+
+	return nil
+}
+
+// SendAlarm sends slack alarm
+func (w *WebSocket) SendAlarm() error {
+	var attachments []slacker.Attachment
+	var blocks []slacker.Block
+	result := w.Result.WebSocket
+
+	// title
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*", w.Name),
+		},
+	})
+
+	// divider
+	blocks = append(blocks, slacker.Block{
+		Type: "divider",
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*URL*: `%s`", result.URL),
+		},
+	})
+
+	blocks = append(blocks, slacker.Block{
+		Type: "section",
+		Text: &slacker.Text{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Connect IP*: `%s`", w.Result.TracingData.ConnectAddr),
+		},
+	})
+
+	if failure := w.Result.Failure; failure != nil {
+		blocks = append(blocks, slacker.Block{
+			Type: "section",
+			Text: &slacker.Text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Failure*: `%s` in %s phase\n%s", failure.Reason, failure.Phase, failure.Message),
+			},
+		})
+	}
+
+	if len(w.Result.Assertions) > 0 {
+		blocks = append(blocks, assertionBlock(w.Result.Assertions))
+	}
+
+	attachments = append(attachments, slacker.Attachment{
+		Color: constants.ErrorColor,
+		Text:  fmt.Sprintf("*WebSocket probe result* - Total Time: %s", w.Result.TracingData.Total.String()),
+		Fields: []slacker.Field{
+			{
+				Title: "Upgrade",
+				Value: result.UpgradeLatency.String(),
+				Short: true,
+			},
+			{
+				Title: "Round Trip",
+				Value: result.RoundTrip.String(),
+				Short: true,
+			},
+		},
+	})
+
+	return sendMessage(w.SlackURL, attachments, blocks)
+}
+
+// SaveData saves data to time-series database
+func (w *WebSocket) SaveData() error {
+	writer := client.NewTimeStreamClient(constants.DefaultRegion)
+	if err := writer.WriteWebSocketData("bigshot", "synthetics", w.Region, w.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+func TestWebSocketProbe(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, append([]byte("echo: "), message...)); err != nil {
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testData := []struct {
+		Name     string
+		Path     string
+		Message  string
+		Expected string
+		Status   int
+		Reason   string
+		Reply    string
+	}{
+		{Name: "upgraded", Path: "/echo", Status: http.StatusSwitchingProtocols},
+		{Name: "not upgraded", Path: "/forbidden", Status: http.StatusForbidden, Reason: constants.ReasonHTTPStatus},
+		{Name: "reply matched", Path: "/echo", Message: "ping", Expected: "^echo: ping$", Status: http.StatusSwitchingProtocols, Reply: "echo: ping"},
+		{Name: "reply missed", Path: "/echo", Message: "ping", Expected: "^pong$", Status: http.StatusSwitchingProtocols, Reason: constants.ReasonCheckFailed, Reply: "echo: ping"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.Name, func(t *testing.T) {
+			w := NewWebSocket("test").(*WebSocket)
+			w.SetTarget(server.URL+td.Path, "")
+			w.Message = td.Message
+			w.Expected = td.Expected
+			w.MessageTimeout = 300 * time.Millisecond
+
+			if err := w.Probe(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := w.Result
+			if result.Response.StatusCode != td.Status {
+				t.Errorf("expected status: %d, got: %d", td.Status, result.Response.StatusCode)
+			}
+
+			if len(td.Reason) == 0 && result.Failed() {
+				t.Errorf("expected success, got: %+v", result.Failure)
+			}
+
+			if len(td.Reason) > 0 && (result.Failure == nil || result.Failure.Reason != td.Reason) {
+				t.Errorf("expected failure reason: %s, got: %+v", td.Reason, result.Failure)
+			}
+
+			if result.WebSocket.Reply != td.Reply {
+				t.Errorf("expected reply: %q, got: %q", td.Reply, result.WebSocket.Reply)
+			}
+		})
+	}
+}
@@ -21,6 +21,158 @@ const TracingTemplate = `{{ decorate "bold" "Domain" }}: {{ format .Summary.Trac
 {{ decorate "bold" "Check IP" }}: {{ format .Summary.TracingData.ConnectAddr }}
 {{ decorate "bold" "Status Code" }}: {{ format .Summary.Response.StatusCode }}
 {{ decorate "bold" "Status Message" }}: {{ format .Summary.Response.StatusMsg }}
+{{- with .Summary.Response.Protocol }}
+{{ decorate "bold" "HTTP Version" }}: {{ .HTTPVersion }}
+{{- if .ALPN }}
+{{ decorate "bold" "ALPN" }}: {{ .ALPN }}
+{{- end }}
+{{- if .TLSVersion }}
+{{ decorate "bold" "TLS Version" }}: {{ .TLSVersion }}
+{{ decorate "bold" "Cipher Suite" }}: {{ .CipherSuite }}
+{{ decorate "bold" "Session Resumed" }}: {{ .SessionResumed }}
+{{ decorate "bold" "OCSP Stapled" }}: {{ .OCSPStapled }}
+{{- end }}
+{{- end }}
+{{- with .Summary.Failure }}
+{{ decorate "bold" "Failure" }}: {{ .Reason }} ({{ .Phase }})
+{{ decorate "bold" "Error" }}: {{ .Message }}
+{{- end }}
+{{- with .Summary.Certificate }}
+{{ decorate "bold" "Certificate" }}: {{ .Subject }}
+{{ decorate "bold" "Issuer" }}: {{ .Issuer }}
+{{ decorate "bold" "Serial" }}: {{ .SerialNumber }}
+{{ decorate "bold" "SANs" }}: {{ format .SANs }}
+{{ decorate "bold" "Not After" }}: {{ .NotAfter }} ({{ .DaysUntilExpiry }} days left)
+{{ decorate "bold" "Chain Verified" }}: {{ .ChainVerified }}
+{{- if .ChainError }}
+{{ decorate "bold" "Chain Error" }}: {{ .ChainError }}
+{{- end }}
+{{- if .Threshold }}
+{{ decorate "bold" "Expiry Alarm" }}: within {{ .Threshold }} days
+{{- end }}
+{{- end }}
+`
+
+// PingTemplate is a template for ping statistics
+const PingTemplate = `{{ decorate "bold" "Host" }}: {{ format .Summary.Addr }}
+{{ decorate "bold" "Ping IP" }}: {{ format .Summary.IPAddr }}
+{{ decorate "bold" "Privileged" }}: {{ format .Summary.Privileged }}
+`
+
+// DNSTemplate is a template for DNS resolution result
+const DNSTemplate = `{{ decorate "bold" "Domain" }}: {{ format .Summary.Name }}
+{{ decorate "bold" "Record Type" }}: {{ format .Summary.RecordType }}
+{{ decorate "bold" "Nameserver" }}: {{ format .Summary.Nameserver }}
+{{ decorate "bold" "Response Code" }}: {{ format .Summary.Rcode }}
+{{ decorate "bold" "Resolution Time" }}: {{ .Summary.ResolutionTime }}
+{{- with .Failure }}
+{{ decorate "bold" "Failure" }}: {{ .Reason }} ({{ .Phase }})
+{{ decorate "bold" "Error" }}: {{ .Message }}
+{{- end }}
+`
+
+// TCPTemplate is a template for TCP connection result
+const TCPTemplate = `{{ decorate "bold" "Address" }}: {{ format .Summary.TCP.Address }}
+{{ decorate "bold" "Connect IP" }}: {{ format .Summary.TracingData.ConnectAddr }}
+{{ decorate "bold" "Bytes Sent" }}: {{ .Summary.TCP.BytesSent }}
+{{ decorate "bold" "Bytes Received" }}: {{ .Summary.TCP.BytesReceived }}
+{{- if .Summary.TCP.Response }}
+{{ decorate "bold" "Response" }}: {{ printf "%q" .Summary.TCP.Response }}
+{{- end }}
+{{- with .Summary.Failure }}
+{{ decorate "bold" "Failure" }}: {{ .Reason }} ({{ .Phase }})
+{{ decorate "bold" "Error" }}: {{ .Message }}
+{{- end }}
+`
+
+// GRPCTemplate is a template for gRPC health checking result
+const GRPCTemplate = `{{ decorate "bold" "Address" }}: {{ format .Summary.GRPC.Address }}
+{{ decorate "bold" "Connect IP" }}: {{ format .Summary.TracingData.ConnectAddr }}
+{{ decorate "bold" "Service" }}: {{ format .Summary.GRPC.Service }}
+{{ decorate "bold" "TLS" }}: {{ .Summary.GRPC.TLS }}
+{{ decorate "bold" "Status" }}: {{ format .Summary.GRPC.Status }}
+{{- if .Summary.GRPC.WatchStatus }}
+{{ decorate "bold" "Watch Status" }}: {{ .Summary.GRPC.WatchStatus }}
+{{- end }}
+{{- with .Summary.Failure }}
+{{ decorate "bold" "Failure" }}: {{ .Reason }} ({{ .Phase }})
+{{ decorate "bold" "Error" }}: {{ .Message }}
+{{- end }}
+`
+
+// WebSocketTemplate is a template for WebSocket probe result
+const WebSocketTemplate = `{{ decorate "bold" "URL" }}: {{ format .Summary.WebSocket.URL }}
+{{ decorate "bold" "Connect IP" }}: {{ format .Summary.TracingData.ConnectAddr }}
+{{- if .Summary.Response.StatusCode }}
+{{ decorate "bold" "Status" }}: {{ .Summary.Response.StatusCode }} {{ .Summary.Response.StatusMsg }}
+{{- end }}
+{{- if .Summary.WebSocket.Subprotocol }}
+{{ decorate "bold" "Subprotocol" }}: {{ .Summary.WebSocket.Subprotocol }}
+{{- end }}
+{{- if .Summary.WebSocket.Reply }}
+{{ decorate "bold" "Reply" }}: {{ printf "%q" .Summary.WebSocket.Reply }}
+{{- end }}
+{{- with .Summary.Failure }}
+{{ decorate "bold" "Failure" }}: {{ .Reason }} ({{ .Phase }})
+{{ decorate "bold" "Error" }}: {{ .Message }}
+{{- end }}
+`
+
+// LoadTemplate is a template for load test metrics
+const LoadTemplate = `{{ decorate "bold" "Target" }}: {{ format .Summary.Method }} {{ format .Summary.URL }}
+{{ decorate "bold" "Duration" }}: {{ .Summary.Duration }}
+{{ decorate "bold" "Requests" }}: {{ .Summary.Requests }} ({{ printf "%.2f" .Summary.Rate }}/s)
+{{ decorate "bold" "Throughput" }}: {{ printf "%.2f" .Summary.Throughput }}/s
+{{ decorate "bold" "Success" }}: {{ printf "%.4f" .Summary.Success }}
+{{ decorate "bold" "Status Codes" }}: {{ statusCodes .Summary.StatusCodes }}
+{{- range $err, $count := .Summary.Errors }}
+  - {{ $err }} ({{ $count }})
+{{- end }}
+`
+
+// LoadReportHTMLTemplate is a HTML template of distributed load test report
+const LoadReportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>bigshot load test report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.global { font-weight: bold; background-color: #eee; }
+td.error { color: #ff0000; text-align: left; }
+</style>
+</head>
+<body>
+{{- range $report := .Reports }}
+<h2>{{ $report.Method }} {{ $report.Target }}</h2>
+<p>Template: {{ $report.Template }} / Rate: {{ $report.Rate }}/s / Duration: {{ $report.Duration }}</p>
+<table>
+<tr><th>Region</th><th>Requests</th><th>Rate</th><th>Throughput</th><th>Success</th><th>Mean</th><th>P50</th><th>P90</th><th>P95</th><th>P99</th><th>Max</th><th>Status Codes</th></tr>
+{{- range $summary := $report.Regions }}
+{{- if $summary.Error }}
+<tr><td>{{ $summary.Region }}</td><td class="error" colspan="11">{{ $summary.Error }}</td></tr>
+{{- else }}
+<tr><td>{{ $summary.Region }}</td><td>{{ $summary.Requests }}</td><td>{{ printf "%.2f" $summary.Rate }}/s</td><td>{{ printf "%.2f" $summary.Throughput }}/s</td><td>{{ percent $summary.Success }}</td><td>{{ $summary.Latencies.Mean }}</td><td>{{ $summary.Latencies.P50 }}</td><td>{{ $summary.Latencies.P90 }}</td><td>{{ $summary.Latencies.P95 }}</td><td>{{ $summary.Latencies.P99 }}</td><td>{{ $summary.Latencies.Max }}</td><td>{{ statusCodes $summary.StatusCodes }}</td></tr>
+{{- end }}
+{{- end }}
+{{- with $report.Global }}
+<tr class="global"><td>{{ .Region }}</td><td>{{ .Requests }}</td><td>{{ printf "%.2f" .Rate }}/s</td><td>{{ printf "%.2f" .Throughput }}/s</td><td>{{ percent .Success }}</td><td>{{ .Latencies.Mean }}</td><td>{{ .Latencies.P50 }}</td><td>{{ .Latencies.P90 }}</td><td>{{ .Latencies.P95 }}</td><td>{{ .Latencies.P99 }}</td><td>{{ .Latencies.Max }}</td><td>{{ statusCodes .StatusCodes }}</td></tr>
+{{- end }}
+</table>
+<table>
+<tr><th>Region</th><th>Error</th><th>Count</th></tr>
+{{- range $summary := $report.Regions }}
+{{- range $err, $count := $summary.Errors }}
+<tr><td>{{ $summary.Region }}</td><td class="error">{{ $err }}</td><td>{{ $count }}</td></tr>
+{{- end }}
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
 `
 
 // ListTemplate is a template of listing bigshot worker settings
@@ -194,7 +194,45 @@ func Int64ToString(n int64) string {
 	return strconv.Itoa(int(n))
 }
 
-// GetExponentialTime returns exponential time difference
+// GetExponentialTime returns base seconds doubled count times up to the maximum backoff
 func GetExponentialTime(base, count int) time.Duration {
-	return time.Duration(1+(2*base-count)) * time.Second
+	max := time.Duration(constants.MaxRetryBackoff) * time.Second
+	if base >= constants.MaxRetryBackoff {
+		return max
+	}
+
+	backoff := time.Duration(base) * time.Second
+	for i := 0; i < count && backoff < max; i++ {
+		backoff *= 2
+	}
+
+	if backoff > max {
+		return max
+	}
+
+	return backoff
+}
+
+// NormalizeYAML changes maps decoded from YAML to map[string]interface{} so that they can be encoded as JSON
+func NormalizeYAML(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range value {
+			m[fmt.Sprintf("%v", k)] = NormalizeYAML(v)
+		}
+		return m
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = NormalizeYAML(v)
+		}
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = NormalizeYAML(v)
+		}
+		return value
+	}
+
+	return v
 }
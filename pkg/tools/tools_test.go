@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExponentialTime(t *testing.T) {
+	testData := []struct {
+		Base   int
+		Count  int
+		Output time.Duration
+	}{
+		{Base: 1, Count: 0, Output: 1 * time.Second},
+		{Base: 1, Count: 3, Output: 8 * time.Second},
+		{Base: 2, Count: 2, Output: 8 * time.Second},
+		{Base: 10, Count: 3, Output: 60 * time.Second},
+		{Base: 1, Count: 100, Output: 60 * time.Second},
+		{Base: 1 << 40, Count: 0, Output: 60 * time.Second},
+	}
+
+	for _, td := range testData {
+		if output := GetExponentialTime(td.Base, td.Count); output != td.Output {
+			t.Errorf("%d, %d - expected: %s, got: %s", td.Base, td.Count, td.Output, output)
+		}
+	}
+}
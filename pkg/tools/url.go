@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+)
+
+// ParseTargetURL parses URL of target with optional port
+// URL without scheme is the legacy form where port is required and https is used only for port 443.
+func ParseTargetURL(rawURL, port string) (*url.URL, error) {
+	raw := strings.TrimSpace(rawURL)
+	if len(raw) == 0 {
+		return nil, errors.New("URL is required")
+	}
+
+	legacy := !strings.Contains(raw, "://")
+	if legacy {
+		// bare IPv6 literal like ::1 should be bracketed
+		if ip := net.ParseIP(raw); ip != nil && strings.Contains(raw, ":") {
+			raw = fmt.Sprintf("[%s]", raw)
+		}
+
+		if len(port) == 0 && !hasPort(raw) {
+			return nil, fmt.Errorf("port or scheme of URL is required: %s", rawURL)
+		}
+
+		scheme := "http"
+		if port == constants.DefaultHTTPSPort {
+			scheme = "https"
+		}
+		raw = fmt.Sprintf("%s://%s", scheme, raw)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if !IsStringInArray(u.Scheme, constants.AllowedSchemes) {
+		return nil, fmt.Errorf("scheme of URL is not allowed: %s", u.Scheme)
+	}
+
+	if len(u.Hostname()) == 0 {
+		return nil, fmt.Errorf("host of URL is required: %s", rawURL)
+	}
+
+	if len(port) > 0 {
+		if len(u.Port()) > 0 && u.Port() != port {
+			return nil, fmt.Errorf("port %s is different from port of URL: %s", port, rawURL)
+		}
+
+		// port 443 of legacy form is omitted as it has been written to time series database
+		if len(u.Port()) == 0 && !(legacy && port == constants.DefaultHTTPSPort) {
+			u.Host = net.JoinHostPort(u.Hostname(), port)
+		}
+	}
+
+	return u, nil
+}
+
+// DefaultPort returns default port of scheme
+func DefaultPort(scheme string) string {
+	switch strings.ToLower(scheme) {
+	case "https", "wss":
+		return constants.DefaultHTTPSPort
+	}
+
+	return constants.DefaultHTTPPort
+}
+
+// IsSecureScheme returns whether scheme uses TLS
+func IsSecureScheme(scheme string) bool {
+	return DefaultPort(scheme) == constants.DefaultHTTPSPort
+}
+
+// hasPort checks whether host part of legacy URL has port
+func hasPort(raw string) bool {
+	u, err := url.Parse("//" + raw)
+	return err == nil && len(u.Port()) > 0
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"testing"
+)
+
+func TestParseTargetURL(t *testing.T) {
+	testData := []struct {
+		URL    string
+		Port   string
+		Output string
+		Error  bool
+	}{
+		{URL: "api.example.com", Port: "443", Output: "https://api.example.com"},
+		{URL: "api.example.com", Port: "80", Output: "http://api.example.com:80"},
+		{URL: "api.example.com/health", Port: "8080", Output: "http://api.example.com:8080/health"},
+		{URL: "api.example.com:8080", Output: "http://api.example.com:8080"},
+		{URL: "api.example.com", Error: true},
+		{URL: "::1", Port: "8080", Output: "http://[::1]:8080"},
+		{URL: "[2001:db8::1]", Port: "443", Output: "https://[2001:db8::1]"},
+		{URL: "https://api.example.com:8443/health?verbose=1", Output: "https://api.example.com:8443/health?verbose=1"},
+		{URL: "http://api.example.com:443", Output: "http://api.example.com:443"},
+		{URL: "HTTPS://api.example.com:443/", Output: "https://api.example.com:443/"},
+		{URL: "http://[::1]:8080/health", Output: "http://[::1]:8080/health"},
+		{URL: "https://api.example.com", Port: "8443", Output: "https://api.example.com:8443"},
+		{URL: "https://api.example.com", Port: "443", Output: "https://api.example.com:443"},
+		{URL: "https://api.example.com:8443", Port: "443", Error: true},
+		{URL: "wss://realtime.example.com/ws", Output: "wss://realtime.example.com/ws"},
+		{URL: "ftp://files.example.com", Error: true},
+		{URL: "https://", Error: true},
+		{URL: "", Port: "80", Error: true},
+	}
+
+	for _, test := range testData {
+		output, err := ParseTargetURL(test.URL, test.Port)
+		if test.Error != (err != nil) {
+			t.Errorf("%s: error expected: %v, got: %v", test.URL, test.Error, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if output.String() != test.Output {
+			t.Errorf("%s: expected: %s, got: %s", test.URL, test.Output, output.String())
+		}
+
+		// normalized URL should be kept as it is
+		if again, err := ParseTargetURL(output.String(), ""); err != nil || again.String() != output.String() {
+			t.Errorf("%s: normalized URL is changed: %v, %v", test.URL, again, err)
+		}
+	}
+}
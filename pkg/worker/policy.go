@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/auth"
+	"github.com/DevopsArtFactory/bigshot/pkg/constants"
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+	"github.com/DevopsArtFactory/bigshot/pkg/tools"
+)
+
+// invokeActions are IAM actions needed to call targets signed with worker role
+var invokeActions = map[string]string{
+	constants.ExecuteAPIService: "execute-api:Invoke",
+	constants.LambdaService:     "lambda:InvokeFunctionUrl",
+}
+
+type policyDocument struct {
+	Version   string
+	Statement []policyStatement
+}
+
+type policyStatement struct {
+	Effect   string
+	Action   []string
+	Resource []string
+}
+
+// InvokePolicyDocument returns policy document which allows worker to call targets signed with sigv4
+// Empty string is returned if no target needs additional permissions.
+func InvokePolicyDocument(targets []schema.Target) (string, error) {
+	actions := map[string]bool{}
+	roles := map[string]bool{}
+	for _, target := range targets {
+		if target.SigV4 == nil {
+			continue
+		}
+
+		if target.SigV4.RoleARN != nil {
+			roles[*target.SigV4.RoleARN] = true
+			continue
+		}
+
+		service := aws.StringValue(target.SigV4.Service)
+		if len(service) == 0 && target.URL != nil {
+			if u, err := tools.ParseTargetURL(*target.URL, aws.StringValue(target.Port)); err == nil {
+				service, _ = auth.ParseAWSEndpoint(u.Hostname())
+			}
+		}
+
+		if action, ok := invokeActions[service]; ok {
+			actions[action] = true
+		}
+	}
+
+	var statements []policyStatement
+	if len(actions) > 0 {
+		statements = append(statements, policyStatement{
+			Effect:   "Allow",
+			Action:   sortedKeys(actions),
+			Resource: []string{"*"},
+		})
+	}
+
+	if len(roles) > 0 {
+		statements = append(statements, policyStatement{
+			Effect:   "Allow",
+			Action:   []string{"sts:AssumeRole"},
+			Resource: sortedKeys(roles),
+		})
+	}
+
+	if len(statements) == 0 {
+		return constants.EmptyString, nil
+	}
+
+	document, err := json.Marshal(policyDocument{
+		Version:   "2012-10-17",
+		Statement: statements,
+	})
+	if err != nil {
+		return constants.EmptyString, err
+	}
+
+	return string(document), nil
+}
+
+// sortedKeys returns keys of m in order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The bigshot Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/DevopsArtFactory/bigshot/pkg/schema"
+)
+
+func TestInvokePolicyDocument(t *testing.T) {
+	testData := []struct {
+		Name    string
+		Targets []schema.Target
+		Output  string
+	}{
+		{
+			Name:    "no sigv4 target",
+			Targets: []schema.Target{{URL: aws.String("https://api.example.com")}},
+			Output:  "",
+		},
+		{
+			Name: "worker role and assumed role",
+			Targets: []schema.Target{
+				{URL: aws.String("https://abcdef1234.execute-api.us-east-1.amazonaws.com/prod"), SigV4: &schema.SigV4Option{}},
+				{URL: aws.String("https://abcdefghijklmnop.lambda-url.us-east-1.on.aws/"), SigV4: &schema.SigV4Option{}},
+				{URL: aws.String("https://api.example.com"), SigV4: &schema.SigV4Option{Service: aws.String("execute-api")}},
+				{URL: aws.String("https://internal.example.com"), SigV4: &schema.SigV4Option{Service: aws.String("execute-api"), RoleARN: aws.String("arn:aws:iam::123456789012:role/synthetics")}},
+			},
+			Output: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["execute-api:Invoke","lambda:InvokeFunctionUrl"],"Resource":["*"]},{"Effect":"Allow","Action":["sts:AssumeRole"],"Resource":["arn:aws:iam::123456789012:role/synthetics"]}]}`,
+		},
+	}
+
+	for _, td := range testData {
+		output, err := InvokePolicyDocument(td.Targets)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output != td.Output {
+			t.Errorf("%s - expected: %s / output: %s", td.Name, td.Output, output)
+		}
+	}
+}
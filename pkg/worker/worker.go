@@ -129,6 +129,10 @@ func (w *Worker) AttachWorkerRolePolicy() error {
 		return err
 	}
 
+	if err := w.PutInvokePolicy(roleName); err != nil {
+		return err
+	}
+
 	roleArn, err := w.IAMClient.FindIamRoleForLambda(roleName)
 	if err != nil {
 		return err
@@ -181,9 +185,29 @@ func (w *Worker) DetachWorkerRolePolicy() error {
 		return err
 	}
 
+	// inline policy should be deleted before role is deleted
+	if err := w.IAMClient.DeleteRolePolicy(roleName, constants.WorkerInvokePolicyName); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// PutInvokePolicy puts inline policy for targets signed with sigv4 to IAM role
+// Policy is deleted if no target needs it any more.
+func (w *Worker) PutInvokePolicy(roleName string) error {
+	document, err := InvokePolicyDocument(w.Config.Targets)
+	if err != nil {
+		return err
+	}
+
+	if len(document) == 0 {
+		return w.IAMClient.DeleteRolePolicy(roleName, constants.WorkerInvokePolicyName)
+	}
+
+	return w.IAMClient.PutRolePolicy(roleName, constants.WorkerInvokePolicyName, document)
+}
+
 // DeleteWorker creates lambda
 func (w *Worker) DeleteWorker() error {
 	err := w.LambdaClient.DeleteFunction(tools.GenerateNewWorkerName(w.Region.Region, w.Config.Name, w.Mode, w.Internal))